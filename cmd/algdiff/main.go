@@ -0,0 +1,403 @@
+/*
+algdiff compares files line by line with the ed/diff package, in the manner of diff(1).
+
+Usage:
+
+	algdiff [flags] fileA fileB
+	algdiff [flags] -r dirA dirB
+
+Flags:
+
+	-u, -U n      unified format with n (default 3) lines of context
+	-c, -C n      context format with n (default 3) lines of context
+	-y            side-by-side format, -W sets the width (default 130)
+	-algorithm    dp, myers, patience or histogram (default)
+	-w            ignore all white space
+	-i            ignore case differences
+	-r            compare directories recursively
+	-stat         print a summary of changed lines for each file
+
+Without a format flag, the normal diff format is used. The exit code is 0 if inputs are the same, 1 if they differ, and 2 for errors.
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/daviddengcn/go-algs/ed/diff"
+	"github.com/daviddengcn/go-algs/ed/render"
+)
+
+// time layouts in headers of the unified and context formats
+const (
+	unifiedTime = "2006-01-02 15:04:05.000000000 -0700"
+	contextTime = "Mon Jan _2 15:04:05 2006"
+)
+
+type options struct {
+	format     string // "normal", "unified", "context" or "side"
+	context    int
+	width      int
+	algorithm  diff.Algorithm
+	ignoreWS   bool
+	ignoreCase bool
+	recursive  bool
+	stat       bool
+}
+
+// stat is the number of changed lines of a file.
+type stat struct {
+	name     string
+	ins, del int
+}
+
+type differ struct {
+	opts   *options
+	stdout io.Writer
+	stderr io.Writer
+	stats  []stat
+	// whether any difference is found, or any error happened
+	differ, failed bool
+}
+
+// key returns the line used for comparing.
+func (opts *options) key(line string) string {
+	if opts.ignoreWS {
+		line = strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, line)
+	}
+	if opts.ignoreCase {
+		line = strings.ToLower(line)
+	}
+
+	return line
+}
+
+// header returns the file name and the modification time in layout.
+func header(fn, layout string) string {
+	if fi, err := os.Stat(fn); err == nil {
+		return fn + "\t" + fi.ModTime().Format(layout)
+	}
+
+	return fn
+}
+
+func (d *differ) errorf(format string, args ...interface{}) {
+	fmt.Fprintf(d.stderr, "algdiff: "+format+"\n", args...)
+	d.failed = true
+}
+
+// checkWrite reports an error of writing to stdout, if any.
+func (d *differ) checkWrite(err error) {
+	if err != nil {
+		d.errorf("%v", err)
+	}
+}
+
+// printf writes to stdout, and reports an error if any.
+func (d *differ) printf(format string, args ...interface{}) {
+	_, err := fmt.Fprintf(d.stdout, format, args...)
+	d.checkWrite(err)
+}
+
+// pairChanges returns a matching where deleted and inserted lines in each change region are paired up, so that side-by-side output shows them as changed lines.
+func pairChanges(matA, matB []int) (pA, pB []int) {
+	pA, pB = append([]int(nil), matA...), append([]int(nil), matB...)
+	for _, h := range diff.Hunks(matA, matB, 0) {
+		for i, j := h.A0, h.B0; i < h.A1 && j < h.B1; i, j = i+1, j+1 {
+			pA[i], pB[j] = j, i
+		}
+	}
+
+	return pA, pB
+}
+
+// diffFiles compares two files. inDir is true if they are found in directories compared recursively.
+func (d *differ) diffFiles(fnA, fnB string, inDir bool) {
+	ba, err := ioutil.ReadFile(fnA)
+	if err != nil {
+		d.errorf("%v", err)
+		return
+	}
+	bb, err := ioutil.ReadFile(fnB)
+	if err != nil {
+		d.errorf("%v", err)
+		return
+	}
+	if bytes.Equal(ba, bb) {
+		return
+	}
+	if bytes.IndexByte(ba, 0) >= 0 || bytes.IndexByte(bb, 0) >= 0 {
+		d.differ = true
+		d.printf("Binary files %s and %s differ\n", fnA, fnB)
+		return
+	}
+
+	var a, b []string
+	var nn diff.NoNewline
+	a, nn.A = diff.SplitLines(string(ba))
+	b, nn.B = diff.SplitLines(string(bb))
+	ka, kb := make([]string, len(a)), make([]string, len(b))
+	for i, l := range a {
+		ka[i] = d.opts.key(l)
+	}
+	for j, l := range b {
+		kb[j] = d.opts.key(l)
+	}
+	// a last line without a newline differs from the same line with one, as in diff(1)
+	if nn.A {
+		ka[len(ka)-1] += "\n"
+	}
+	if nn.B {
+		kb[len(kb)-1] += "\n"
+	}
+	matA, matB := d.opts.algorithm(ka, kb)
+
+	st := stat{name: fnB}
+	for _, j := range matA {
+		if j < 0 {
+			st.del++
+		}
+	}
+	for _, i := range matB {
+		if i < 0 {
+			st.ins++
+		}
+	}
+	if st.del == 0 && st.ins == 0 {
+		return
+	}
+	d.differ = true
+
+	if d.opts.stat {
+		d.stats = append(d.stats, st)
+		return
+	}
+	if inDir {
+		d.printf("diff %s %s\n", fnA, fnB)
+	}
+	switch d.opts.format {
+	case "unified":
+		d.checkWrite(diff.WriteUnifiedNoNewline(d.stdout, header(fnA, unifiedTime), header(fnB, unifiedTime), a, b, matA, matB, d.opts.context, nn))
+	case "context":
+		d.checkWrite(diff.WriteContextNoNewline(d.stdout, header(fnA, contextTime), header(fnB, contextTime), a, b, matA, matB, d.opts.context, nn))
+	case "side":
+		opts := render.Strings(a, b)
+		opts.Width = d.opts.width
+		opts.Equal = func(iA, iB int) bool {
+			return ka[iA] == kb[iB]
+		}
+		pA, pB := pairChanges(matA, matB)
+		d.checkWrite(render.SideBySide(d.stdout, pA, pB, opts))
+	default:
+		d.checkWrite(diff.WriteNormalNoNewline(d.stdout, a, b, matA, matB, nn))
+	}
+}
+
+func readDirNames(dir string) (map[string]os.FileInfo, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]os.FileInfo)
+	for _, fi := range fis {
+		m[fi.Name()] = fi
+	}
+
+	return m, nil
+}
+
+func (d *differ) diffDirs(dirA, dirB string) {
+	fisA, err := readDirNames(dirA)
+	if err != nil {
+		d.errorf("%v", err)
+		return
+	}
+	fisB, err := readDirNames(dirB)
+	if err != nil {
+		d.errorf("%v", err)
+		return
+	}
+
+	var names []string
+	for n := range fisA {
+		names = append(names, n)
+	}
+	for n := range fisB {
+		if _, ok := fisA[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		fa, okA := fisA[n]
+		fb, okB := fisB[n]
+		pa, pb := filepath.Join(dirA, n), filepath.Join(dirB, n)
+		switch {
+		case !okB:
+			d.differ = true
+			d.printf("Only in %s: %s\n", dirA, n)
+		case !okA:
+			d.differ = true
+			d.printf("Only in %s: %s\n", dirB, n)
+		case fa.IsDir() && fb.IsDir():
+			d.diffDirs(pa, pb)
+		case fa.IsDir() || fb.IsDir():
+			d.differ = true
+			d.printf("File %s is a %s while file %s is a %s\n", pa, kind(fa), pb, kind(fb))
+		default:
+			d.diffFiles(pa, pb, true)
+		}
+	}
+}
+
+func kind(fi os.FileInfo) string {
+	if fi.IsDir() {
+		return "directory"
+	}
+
+	return "regular file"
+}
+
+// writeStat writes the summary like git diff --stat.
+func (d *differ) writeStat() {
+	if len(d.stats) == 0 {
+		return
+	}
+
+	nameW, maxN := 0, 0
+	for _, st := range d.stats {
+		if len(st.name) > nameW {
+			nameW = len(st.name)
+		}
+		if n := st.ins + st.del; n > maxN {
+			maxN = n
+		}
+	}
+	// the width of the bar
+	barW := 50
+	ins, del := 0, 0
+	for _, st := range d.stats {
+		ni, nd := st.ins, st.del
+		if maxN > barW {
+			ni, nd = (ni*barW+maxN-1)/maxN, (nd*barW+maxN-1)/maxN
+		}
+		d.printf(" %-*s | %*d %s%s\n", nameW, st.name, len(fmt.Sprint(maxN)), st.ins+st.del, strings.Repeat("+", ni), strings.Repeat("-", nd))
+		ins += st.ins
+		del += st.del
+	}
+	d.printf(" %d file%s changed, %d insertion%s(+), %d deletion%s(-)\n", len(d.stats), plural(len(d.stats)), ins, plural(ins), del, plural(del))
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+
+	return "s"
+}
+
+// run runs the command and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("algdiff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	u := fs.Bool("u", false, "output in the unified format with 3 lines of context")
+	uN := fs.Int("U", -1, "output in the unified format with `n` lines of context")
+	c := fs.Bool("c", false, "output in the context format with 3 lines of context")
+	cN := fs.Int("C", -1, "output in the context format with `n` lines of context")
+	y := fs.Bool("y", false, "output in two columns")
+	width := fs.Int("W", 130, "output at most `n` columns for -y")
+	algo := fs.String("algorithm", "histogram", "dp, myers, patience or histogram")
+	opts := &options{}
+	fs.BoolVar(&opts.ignoreWS, "w", false, "ignore all white space")
+	fs.BoolVar(&opts.ignoreCase, "i", false, "ignore case differences")
+	fs.BoolVar(&opts.recursive, "r", false, "recursively compare subdirectories")
+	fs.BoolVar(&opts.stat, "stat", false, "output a summary of changed lines")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: algdiff [flags] fileA fileB")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	opts.format, opts.context, opts.width = "normal", 3, *width
+	switch {
+	case *u || *uN >= 0:
+		opts.format = "unified"
+		if *uN >= 0 {
+			opts.context = *uN
+		}
+	case *c || *cN >= 0:
+		opts.format = "context"
+		if *cN >= 0 {
+			opts.context = *cN
+		}
+	case *y:
+		opts.format = "side"
+	}
+	if opts.algorithm = diff.Algorithms[*algo]; opts.algorithm == nil {
+		fmt.Fprintf(stderr, "algdiff: unknown algorithm %q\n", *algo)
+		return 2
+	}
+
+	d := &differ{opts: opts, stdout: stdout, stderr: stderr}
+	a, b := fs.Arg(0), fs.Arg(1)
+	fa, err := os.Stat(a)
+	if err != nil {
+		fmt.Fprintf(stderr, "algdiff: %v\n", err)
+		return 2
+	}
+	fb, err := os.Stat(b)
+	if err != nil {
+		fmt.Fprintf(stderr, "algdiff: %v\n", err)
+		return 2
+	}
+	switch {
+	case fa.IsDir() && fb.IsDir():
+		if !opts.recursive {
+			fmt.Fprintln(stderr, "algdiff: use -r to compare directories")
+			return 2
+		}
+		d.diffDirs(a, b)
+	case fa.IsDir():
+		d.diffFiles(filepath.Join(a, filepath.Base(b)), b, false)
+	case fb.IsDir():
+		d.diffFiles(a, filepath.Join(b, filepath.Base(a)), false)
+	default:
+		d.diffFiles(a, b, false)
+	}
+	if opts.stat {
+		d.writeStat()
+	}
+
+	switch {
+	case d.failed:
+		return 2
+	case d.differ:
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func runArgs(args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		fn := filepath.Join(dir, name)
+		assert.NoErrorOrDie(t, os.MkdirAll(filepath.Dir(fn), 0755))
+		assert.NoErrorOrDie(t, ioutil.WriteFile(fn, []byte(content), 0644))
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "algdiff")
+	assert.NoErrorOrDie(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"a.txt": "a\nb\nc\n",
+		"b.txt": "a\nB\nc\nd\n",
+		"c.txt": "A\n b\nc\nd\n",
+	})
+	fa, fb, fc := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "c.txt")
+
+	test := func(args []string, code int, stdout string) {
+		actCode, actOut, _ := runArgs(args...)
+		assert.Equal(t, "exit code", actCode, code)
+		assert.Equal(t, "stdout", actOut, stdout)
+	}
+
+	test([]string{fa, fa}, 0, "")
+	test([]string{fa, fb}, 1, "2c2\n< b\n---\n> B\n3a4\n> d\n")
+	test([]string{"-algorithm=histogram", fa, fb}, 1, "2c2\n< b\n---\n> B\n3a4\n> d\n")
+	test([]string{"-i", fa, fb}, 1, "3a4\n> d\n")
+	test([]string{"-i", fb, fc}, 1, "2c2\n< B\n---\n>  b\n")
+	test([]string{"-i", "-w", fb, fc}, 0, "")
+	test([]string{"-y", "-W", "21", fa, fb}, 1, "a           a\nb         | B\nc           c\n          > d\n")
+	test([]string{"-algorithm=unknown", fa, fb}, 2, "")
+	test([]string{fa, filepath.Join(dir, "missing.txt")}, 2, "")
+	test([]string{fa}, 2, "")
+
+	_, out, _ := runArgs("-u", fa, fb)
+	lines := strings.Split(out, "\n")
+	assert.True(t, "--- header", strings.HasPrefix(lines[0], "--- "+fa+"\t"))
+	assert.True(t, "+++ header", strings.HasPrefix(lines[1], "+++ "+fb+"\t"))
+	assert.Equal(t, "hunks", strings.Join(lines[2:], "\n"), "@@ -1,3 +1,4 @@\n a\n-b\n+B\n c\n+d\n")
+
+	_, out, _ = runArgs("-C", "0", fa, fb)
+	lines = strings.Split(out, "\n")
+	assert.True(t, "*** header", strings.HasPrefix(lines[0], "*** "+fa+"\t"))
+	assert.Equal(t, "hunks", strings.Join(lines[2:], "\n"), "***************\n*** 2 ****\n! b\n--- 2 ----\n! B\n***************\n*** 3 ****\n--- 4 ----\n+ d\n")
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestRun_WriteError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "algdiff")
+	assert.NoErrorOrDie(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{"a.txt": "a\n", "b.txt": "b\n"})
+	fa, fb := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	for _, flag := range []string{"-u", "-c", "-y", "-stat", "-i"} {
+		var errOut bytes.Buffer
+		code := run([]string{flag, fa, fb}, failWriter{}, &errOut)
+		assert.Equal(t, flag+" exit code", code, 2)
+		assert.True(t, flag+" stderr", strings.HasPrefix(errOut.String(), "algdiff: broken pipe\n"))
+	}
+}
+
+func TestRun_NoNewline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "algdiff")
+	assert.NoErrorOrDie(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"x1": "a\nb",
+		"x2": "a\nb\n",
+		"x3": "a\nc",
+	})
+	x1, x2, x3 := filepath.Join(dir, "x1"), filepath.Join(dir, "x2"), filepath.Join(dir, "x3")
+
+	code, out, _ := runArgs(x1, x2)
+	assert.Equal(t, "exit code", code, 1)
+	assert.Equal(t, "normal", out, "2c2\n< b\n\\ No newline at end of file\n---\n> b\n")
+	code, out, _ = runArgs(x1, x3)
+	assert.Equal(t, "exit code", code, 1)
+	assert.Equal(t, "normal", out, "2c2\n< b\n\\ No newline at end of file\n---\n> c\n\\ No newline at end of file\n")
+
+	_, out, _ = runArgs("-u", x1, x2)
+	lines := strings.Split(out, "\n")
+	assert.Equal(t, "unified", strings.Join(lines[2:], "\n"), "@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n")
+
+	_, out, _ = runArgs("-c", x3, x1)
+	lines = strings.Split(out, "\n")
+	assert.Equal(t, "context", strings.Join(lines[2:], "\n"), "***************\n*** 1,2 ****\n  a\n! c\n\\ No newline at end of file\n--- 1,2 ----\n  a\n! b\n\\ No newline at end of file\n")
+
+	code, _, _ = runArgs(x1, x1)
+	assert.Equal(t, "same", code, 0)
+}
+
+func TestRun_Dirs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "algdiff")
+	assert.NoErrorOrDie(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"a/same.txt":  "x\n",
+		"a/x.txt":     "a\nb\nc\n",
+		"a/sub/y.txt": "q\n",
+		"a/onlya.txt": "1\n",
+		"a/bin":       "a\x00b",
+		"b/same.txt":  "x\n",
+		"b/x.txt":     "a\nB\nc\nd\n",
+		"b/sub/y.txt": "r\n",
+		"b/onlyb.txt": "2\n",
+		"b/bin":       "a\x00c",
+	})
+	da, db := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	pa := func(name string) string { return filepath.Join(da, name) }
+	pb := func(name string) string { return filepath.Join(db, name) }
+
+	code, _, _ := runArgs(da, db)
+	assert.Equal(t, "exit code", code, 2)
+
+	code, out, _ := runArgs("-r", da, db)
+	assert.Equal(t, "exit code", code, 1)
+	assert.Equal(t, "stdout", out, "Binary files "+pa("bin")+" and "+pb("bin")+" differ\n"+
+		"Only in "+da+": onlya.txt\n"+
+		"Only in "+db+": onlyb.txt\n"+
+		"diff "+pa("sub/y.txt")+" "+pb("sub/y.txt")+"\n1c1\n< q\n---\n> r\n"+
+		"diff "+pa("x.txt")+" "+pb("x.txt")+"\n2c2\n< b\n---\n> B\n3a4\n> d\n")
+
+	code, out, _ = runArgs("-r", "-stat", da, db)
+	assert.Equal(t, "exit code", code, 1)
+	assert.True(t, "stat summary", strings.HasSuffix(out, " 2 files changed, 3 insertions(+), 2 deletions(-)\n"))
+	assert.True(t, "stat of x.txt", strings.Contains(out, " "+pb("x.txt")+"     | 3 ++-\n"))
+}
@@ -0,0 +1,277 @@
+/*
+editdist computes distances between strings with the ed package.
+
+Usage:
+
+	editdist [flags] A B
+	editdist [flags] -files fileA fileB
+	editdist [flags] -lines fileA fileB
+
+The first form compares two arguments, the second compares the contents of two files, and the third compares every line of fileA with every line of fileB.
+
+Metrics (-metric):
+
+	levenshtein   ed.String, the default
+	damerau       ed.Damerau, with transpositions
+	jarowinkler   ed.JaroWinkler similarity
+	weighted      ed.EditDistanceF with costs -del, -ins and -sub
+
+Output formats (-format) are text, csv and json.
+
+If -threshold is set, the exit code is 1 if any distance is greater than the threshold, or any similarity (for jarowinkler) is less than the threshold. The exit code is 2 for errors.
+*/
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// result is the distance between a pair. Line numbers are 1-based, and 0 if not in -lines mode.
+type result struct {
+	LineA    int     `json:"lineA,omitempty"`
+	LineB    int     `json:"lineB,omitempty"`
+	A        string  `json:"a"`
+	B        string  `json:"b"`
+	Distance float64 `json:"distance"`
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func metricFunc(metric string, del, ins, sub int) (func(a, b string) float64, error) {
+	switch metric {
+	case "levenshtein":
+		return func(a, b string) float64 {
+			return float64(ed.String(a, b))
+		}, nil
+	case "damerau":
+		return func(a, b string) float64 {
+			return float64(ed.Damerau(a, b))
+		}, nil
+	case "jarowinkler":
+		return ed.JaroWinkler, nil
+	case "weighted":
+		return func(a, b string) float64 {
+			ra, rb := []rune(a), []rune(b)
+			return float64(ed.EditDistanceF(len(ra), len(rb), func(iA, iB int) int {
+				return ed.Ternary(ra[iA] == rb[iB], 0, sub)
+			}, ed.ConstCost(del), ed.ConstCost(ins)))
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown metric %q", metric)
+}
+
+func readLines(fn string) ([]string, error) {
+	bs, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	s := strings.TrimSuffix(string(bs), "\n")
+	if s == "" {
+		return nil, nil
+	}
+
+	return strings.Split(s, "\n"), nil
+}
+
+// resultWriter writes results one by one as soon as they are computed.
+type resultWriter struct {
+	w      io.Writer
+	format string
+	lines  bool
+	cw     *csv.Writer
+	// n is the number of results written
+	n int
+}
+
+func newResultWriter(w io.Writer, format string, lines bool) (*resultWriter, error) {
+	rw := &resultWriter{w: w, format: format, lines: lines}
+	switch format {
+	case "text", "json":
+	case "csv":
+		rw.cw = csv.NewWriter(w)
+		header := []string{"a", "b", "distance"}
+		if lines {
+			header = []string{"lineA", "lineB", "a", "b", "distance"}
+		}
+		rw.cw.Write(header)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	return rw, nil
+}
+
+func (rw *resultWriter) write(r result) error {
+	rw.n++
+	switch rw.format {
+	case "text":
+		if rw.lines {
+			_, err := fmt.Fprintf(rw.w, "%d\t%d\t%s\n", r.LineA, r.LineB, formatFloat(r.Distance))
+			return err
+		}
+		_, err := fmt.Fprintln(rw.w, formatFloat(r.Distance))
+		return err
+
+	case "csv":
+		rec := []string{r.A, r.B, formatFloat(r.Distance)}
+		if rw.lines {
+			rec = append([]string{strconv.Itoa(r.LineA), strconv.Itoa(r.LineB)}, rec...)
+		}
+		rw.cw.Write(rec)
+		return rw.cw.Error()
+	}
+
+	// json: elements of an indented array
+	bs, err := json.MarshalIndent(r, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if rw.n == 1 {
+		sep = "[\n  "
+	}
+	_, err = fmt.Fprintf(rw.w, "%s%s", sep, bs)
+	return err
+}
+
+// close finishes the output.
+func (rw *resultWriter) close() error {
+	switch rw.format {
+	case "csv":
+		rw.cw.Flush()
+		return rw.cw.Error()
+	case "json":
+		end := "\n]\n"
+		if rw.n == 0 {
+			end = "[]\n"
+		}
+		_, err := io.WriteString(rw.w, end)
+		return err
+	}
+
+	return nil
+}
+
+// run runs the command and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("editdist", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	metric := fs.String("metric", "levenshtein", "levenshtein, damerau, jarowinkler or weighted")
+	format := fs.String("format", "text", "output format: text, csv or json")
+	files := fs.Bool("files", false, "compare the contents of two files")
+	lines := fs.Bool("lines", false, "compare every line pair of two files")
+	del := fs.Int("del", 1, "cost of deleting a rune, for -metric=weighted")
+	ins := fs.Int("ins", 1, "cost of inserting a rune, for -metric=weighted")
+	sub := fs.Int("sub", 1, "cost of changing a rune, for -metric=weighted")
+	threshold := fs.Float64("threshold", -1, "exit with 1 if any distance is greater than (similarity is less than) this value; negative to disable")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: editdist [flags] A B | -files fileA fileB | -lines fileA fileB")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 || *files && *lines {
+		fs.Usage()
+		return 2
+	}
+
+	// validate all flags before doing any work
+	if *metric == "weighted" && (*del < 0 || *ins < 0 || *sub < 0) {
+		fmt.Fprintln(stderr, "editdist: costs must be non-negative")
+		return 2
+	}
+	dist, err := metricFunc(*metric, *del, *ins, *sub)
+	if err != nil {
+		fmt.Fprintln(stderr, "editdist:", err)
+		return 2
+	}
+	out := bufio.NewWriter(stdout)
+	rw, err := newResultWriter(out, *format, *lines)
+	if err != nil {
+		fmt.Fprintln(stderr, "editdist:", err)
+		return 2
+	}
+
+	// werr is the first error of writing
+	exceeded, werr := false, error(nil)
+	emit := func(r result) bool {
+		if *threshold >= 0 && (*metric == "jarowinkler" && r.Distance < *threshold || *metric != "jarowinkler" && r.Distance > *threshold) {
+			exceeded = true
+		}
+		werr = rw.write(r)
+		return werr == nil
+	}
+	a, b := fs.Arg(0), fs.Arg(1)
+	switch {
+	case *lines:
+		la, err := readLines(a)
+		if err != nil {
+			fmt.Fprintln(stderr, "editdist:", err)
+			return 2
+		}
+		lb, err := readLines(b)
+		if err != nil {
+			fmt.Fprintln(stderr, "editdist:", err)
+			return 2
+		}
+	rows:
+		for i, sa := range la {
+			for j, sb := range lb {
+				if !emit(result{LineA: i + 1, LineB: j + 1, A: sa, B: sb, Distance: dist(sa, sb)}) {
+					break rows
+				}
+			}
+		}
+
+	case *files:
+		ba, err := ioutil.ReadFile(a)
+		if err != nil {
+			fmt.Fprintln(stderr, "editdist:", err)
+			return 2
+		}
+		bb, err := ioutil.ReadFile(b)
+		if err != nil {
+			fmt.Fprintln(stderr, "editdist:", err)
+			return 2
+		}
+		emit(result{A: a, B: b, Distance: dist(string(ba), string(bb))})
+
+	default:
+		emit(result{A: a, B: b, Distance: dist(a, b)})
+	}
+	if werr == nil {
+		werr = rw.close()
+	}
+	if werr == nil {
+		werr = out.Flush()
+	}
+	if werr != nil {
+		fmt.Fprintln(stderr, "editdist:", werr)
+		return 2
+	}
+
+	if exceeded {
+		return 1
+	}
+
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
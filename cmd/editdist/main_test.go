@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func runArgs(args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestRun(t *testing.T) {
+	test := func(args []string, code int, stdout string) {
+		actCode, actOut, _ := runArgs(args...)
+		assert.Equal(t, "exit code", actCode, code)
+		assert.Equal(t, "stdout", actOut, stdout)
+	}
+
+	test([]string{"abcd", "bcde"}, 0, "2\n")
+	test([]string{"-metric=damerau", "ab", "ba"}, 0, "1\n")
+	test([]string{"-metric=weighted", "-sub=3", "ab", "ac"}, 0, "2\n")
+	test([]string{"-metric=jarowinkler", "abc", "abc"}, 0, "1\n")
+	test([]string{"-threshold=1", "abcd", "bcde"}, 1, "2\n")
+	test([]string{"-threshold=2", "abcd", "bcde"}, 0, "2\n")
+	test([]string{"-metric=jarowinkler", "-threshold=0.5", "abc", "xyz"}, 1, "0\n")
+	test([]string{"-format=csv", "a,b", "b"}, 0, "a,b,distance\n\"a,b\",b,2\n")
+	test([]string{"-format=json", "a", "b"}, 0, "[\n  {\n    \"a\": \"a\",\n    \"b\": \"b\",\n    \"distance\": 1\n  }\n]\n")
+	test([]string{"-metric=unknown", "a", "b"}, 2, "")
+	test([]string{"a"}, 2, "")
+	test([]string{"-metric=weighted", "-del=-1", "a", "b"}, 2, "")
+}
+
+func TestRun_InvalidFormat(t *testing.T) {
+	// flags are validated before reading any files
+	code, out, errOut := runArgs("-format=xml", "-files", "missing-a.txt", "missing-b.txt")
+	assert.Equal(t, "exit code", code, 2)
+	assert.Equal(t, "stdout", out, "")
+	assert.Equal(t, "stderr", errOut, "editdist: unknown format \"xml\"\n")
+
+	_, _, errOut = runArgs("-metric=weighted", "-del=-1", "a", "b")
+	assert.Equal(t, "stderr", errOut, "editdist: costs must be non-negative\n")
+	_, _, errOut = runArgs("-metric=unknown", "a", "b")
+	assert.Equal(t, "stderr", errOut, "editdist: unknown metric \"unknown\"\n")
+}
+
+// failWriter fails after writing n bytes.
+type failWriter struct {
+	n int
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errors.New("disk full")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestRun_Lines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "editdist")
+	assert.NoErrorOrDie(t, err)
+	defer os.RemoveAll(dir)
+
+	// 300 x 300 line pairs, more than the buffer of the output
+	var sb strings.Builder
+	for i := 0; i < 300; i++ {
+		fmt.Fprintf(&sb, "line %d\n", i)
+	}
+	fa := filepath.Join(dir, "a.txt")
+	assert.NoErrorOrDie(t, ioutil.WriteFile(fa, []byte(sb.String()), 0644))
+
+	code, out, _ := runArgs("-lines", "-format=json", fa, fa)
+	assert.Equal(t, "exit code", code, 0)
+	var res []result
+	assert.NoErrorOrDie(t, json.Unmarshal([]byte(out), &res))
+	assert.Equal(t, "len(res)", len(res), 90000)
+	assert.Equal(t, "res[1]", res[1], result{LineA: 1, LineB: 2, A: "line 0", B: "line 1", Distance: 1})
+
+	// a failed write stops computing and is reported
+	var errOut bytes.Buffer
+	code = run([]string{"-lines", fa, fa}, &failWriter{n: 10000}, &errOut)
+	assert.Equal(t, "exit code", code, 2)
+	assert.Equal(t, "stderr", errOut.String(), "editdist: disk full\n")
+}
+
+func TestRun_Files(t *testing.T) {
+	dir, err := ioutil.TempDir("", "editdist")
+	assert.NoErrorOrDie(t, err)
+	defer os.RemoveAll(dir)
+
+	fa, fb := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	assert.NoErrorOrDie(t, ioutil.WriteFile(fa, []byte("abc\nxyz\n"), 0644))
+	assert.NoErrorOrDie(t, ioutil.WriteFile(fb, []byte("abd\n"), 0644))
+
+	code, out, _ := runArgs("-files", fa, fb)
+	assert.Equal(t, "exit code", code, 0)
+	assert.Equal(t, "stdout", out, "5\n")
+
+	code, out, _ = runArgs("-lines", fa, fb)
+	assert.Equal(t, "exit code", code, 0)
+	assert.Equal(t, "stdout", out, "1\t1\t1\n2\t1\t3\n")
+
+	code, out, _ = runArgs("-lines", "-format=csv", fa, fb)
+	assert.Equal(t, "exit code", code, 0)
+	assert.Equal(t, "stdout", out, "lineA,lineB,a,b,distance\n1,1,abc,abd,1\n2,1,xyz,abd,3\n")
+
+	code, _, _ = runArgs("-files", fa, filepath.Join(dir, "missing.txt"))
+	assert.Equal(t, "exit code", code, 2)
+}
@@ -0,0 +1,272 @@
+/*
+dtw package implements the dynamic time warping (DTW) algorithm, which aligns two series of different speeds.
+
+DTW has the same DP shape as the edit-distance in the ed package, but instead of deleting and inserting, each step of the warping path matches a[iA] to b[iB] and advances in A, in B or in both. The distance is the sum of costs of matched pairs along the path.
+
+The search space can be limited by a Window, e.g. SakoeChiba or Itakura, which prevents pathological warpings and is faster to compute, as only cells in the window are visited.
+*/
+package dtw
+
+import (
+	"math"
+)
+
+/*
+Interface defines a pair of series and the cost of matching their elements.
+*/
+type Interface interface {
+	// LenA returns the length of series A
+	LenA() int
+
+	// LenB returns the length of series B
+	LenB() int
+
+	// CostOfMatch returns the cost of matching a[iA] with b[iB], usually the distance between them
+	CostOfMatch(iA, iB int) float64
+}
+
+type series struct {
+	a, b []float64
+}
+
+// Interface.LenA
+func (s *series) LenA() int {
+	return len(s.a)
+}
+
+// Interface.LenB
+func (s *series) LenB() int {
+	return len(s.b)
+}
+
+// Interface.CostOfMatch
+func (s *series) CostOfMatch(iA, iB int) float64 {
+	return math.Abs(s.a[iA] - s.b[iB])
+}
+
+// Series returns an Interface of two univariate series, where the cost of matching is the absolute difference.
+func Series(a, b []float64) Interface {
+	return &series{a: a, b: b}
+}
+
+type points struct {
+	a, b [][]float64
+}
+
+// Interface.LenA
+func (p *points) LenA() int {
+	return len(p.a)
+}
+
+// Interface.LenB
+func (p *points) LenB() int {
+	return len(p.b)
+}
+
+// Interface.CostOfMatch
+func (p *points) CostOfMatch(iA, iB int) float64 {
+	s := 0.0
+	for k, v := range p.a[iA] {
+		d := v - p.b[iB][k]
+		s += d * d
+	}
+	return math.Sqrt(s)
+}
+
+// Points returns an Interface of two multivariate series, where the cost of matching is the Euclidean distance. All points must have the same dimension.
+func Points(a, b [][]float64) Interface {
+	return &points{a: a, b: b}
+}
+
+/*
+Window returns the range [lo, hi] of indexes of B allowed to be matched with a[iA], where la and lb are lengths of the series. Only cells in the ranges are computed, so a narrow Window is faster. The range is empty if lo > hi. A nil Window allows all pairs.
+*/
+type Window func(iA, la, lb int) (lo, hi int)
+
+// rowRange returns the range of row iA clipped to [0, lb-1].
+func (w Window) rowRange(iA, la, lb int) (lo, hi int) {
+	if w == nil {
+		return 0, lb - 1
+	}
+	lo, hi = w(iA, la, lb)
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > lb-1 {
+		hi = lb - 1
+	}
+	return lo, hi
+}
+
+// Allows reports whether a[iA] is allowed to be matched with b[iB].
+func (w Window) Allows(iA, iB, la, lb int) bool {
+	lo, hi := w.rowRange(iA, la, lb)
+	return lo <= iB && iB <= hi
+}
+
+// scaled returns iA scaled to the range of B.
+func scaled(iA, la, lb int) float64 {
+	if la <= 1 {
+		return 0
+	}
+	return float64(iA) * float64(lb-1) / float64(la-1)
+}
+
+const eps = 1e-9
+
+// SakoeChiba returns the Sakoe-Chiba band Window, which allows pairs within radius r of the diagonal. For series of different lengths, the diagonal is scaled.
+func SakoeChiba(r int) Window {
+	return func(iA, la, lb int) (lo, hi int) {
+		c := scaled(iA, la, lb)
+		return int(math.Ceil(c - float64(r) - eps)), int(math.Floor(c + float64(r) + eps))
+	}
+}
+
+/*
+Itakura returns the Itakura parallelogram Window with a maximum slope (greater than 1), i.e. the local speed of one series is at most slope times of the other, in coordinates scaled to the same length.
+*/
+func Itakura(slope float64) Window {
+	return func(iA, la, lb int) (lo, hi int) {
+		if la <= 1 || lb <= 1 {
+			return 0, lb - 1
+		}
+		// y is in [x/slope, slope*x] from the start, and 1-y in [(1-x)/slope, slope*(1-x)] from the end
+		x := float64(iA) / float64(la-1)
+		yLo := math.Max(x/slope, 1-slope*(1-x))
+		yHi := math.Min(slope*x, 1-(1-x)/slope)
+		n := float64(lb - 1)
+		return int(math.Ceil(yLo*n - eps)), int(math.Floor(yHi*n + eps))
+	}
+}
+
+// Point is a pair of matched indexes in a warping path.
+type Point struct {
+	A, B int
+}
+
+/*
+Distance returns the DTW distance, or +Inf if there is no warping path within the window or either series is empty.
+
+The time complexity is O(mn) where m and n are lengths of the two series, or O(mr) with a window of width r, and space complexity is O(n). Only costs of pairs within the window are computed.
+*/
+func Distance(in Interface, w Window) float64 {
+	la, lb := in.LenA(), in.LenB()
+	if la == 0 || lb == 0 {
+		return math.Inf(1)
+	}
+
+	inf := math.Inf(1)
+	// f is the row iA over [lo, hi], f1 is the row iA-1 over [lo1, hi1]
+	var f1, f []float64
+	lo1, hi1 := 0, -1
+	at := func(row []float64, lo, hi, iB int) float64 {
+		if iB < lo || iB > hi {
+			return inf
+		}
+		return row[iB-lo]
+	}
+	for iA := 0; iA < la; iA++ {
+		lo, hi := w.rowRange(iA, la, lb)
+		f = f[:0]
+		for iB := lo; iB <= hi; iB++ {
+			var mn float64
+			switch {
+			case iA == 0 && iB == 0:
+				mn = 0
+			case iB == lo:
+				mn = math.Min(at(f1, lo1, hi1, iB-1), at(f1, lo1, hi1, iB))
+			default:
+				mn = math.Min(math.Min(at(f1, lo1, hi1, iB-1), at(f1, lo1, hi1, iB)), f[iB-1-lo])
+			}
+			if !math.IsInf(mn, 1) {
+				mn += in.CostOfMatch(iA, iB)
+			}
+			f = append(f, mn)
+		}
+		f1, f = f, f1
+		lo1, hi1 = lo, hi
+	}
+	return at(f1, lo1, hi1, lb-1)
+}
+
+/*
+DistanceFull returns the DTW distance and the warping path, which starts at (0, 0) and ends at (la-1, lb-1), and each step increases A, B or both by one. The path is nil if the distance is +Inf.
+
+Mapping converts the path to a matching analogous to matA/matB of the ed package.
+
+The time and space complexities are O(mn) where m and n are lengths of the two series, or O(mr) with a window of width r.
+*/
+func DistanceFull(in Interface, w Window) (dist float64, path []Point) {
+	la, lb := in.LenA(), in.LenB()
+	if la == 0 || lb == 0 {
+		return math.Inf(1), nil
+	}
+
+	inf := math.Inf(1)
+	// f[iA] is the row iA over [los[iA], his[iA]]
+	f := make([][]float64, la)
+	los, his := make([]int, la), make([]int, la)
+	at := func(iA, iB int) float64 {
+		if iA < 0 || iB < los[iA] || iB > his[iA] {
+			return inf
+		}
+		return f[iA][iB-los[iA]]
+	}
+	for iA := range f {
+		lo, hi := w.rowRange(iA, la, lb)
+		los[iA], his[iA] = lo, hi
+		for iB := lo; iB <= hi; iB++ {
+			var mn float64
+			if iA == 0 && iB == 0 {
+				mn = 0
+			} else {
+				mn = math.Min(math.Min(at(iA-1, iB-1), at(iA-1, iB)), at(iA, iB-1))
+			}
+			if !math.IsInf(mn, 1) {
+				mn += in.CostOfMatch(iA, iB)
+			}
+			f[iA] = append(f[iA], mn)
+		}
+	}
+
+	dist = at(la-1, lb-1)
+	if math.IsInf(dist, 1) {
+		return dist, nil
+	}
+
+	// backtrack, preferring diagonal steps
+	iA, iB := la-1, lb-1
+	path = append(path, Point{iA, iB})
+	for iA > 0 || iB > 0 {
+		switch {
+		case iA == 0:
+			iB--
+		case iB == 0:
+			iA--
+		case at(iA-1, iB-1) <= at(iA-1, iB) && at(iA-1, iB-1) <= at(iA, iB-1):
+			iA--
+			iB--
+		case at(iA-1, iB) <= at(iA, iB-1):
+			iA--
+		default:
+			iB--
+		}
+		path = append(path, Point{iA, iB})
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return dist, path
+}
+
+/*
+Mapping converts a warping path to matchings of series with lengths la and lb: matA[iA] is the list of indexes in B matched with a[iA], in increasing order, and similarly for matB.
+*/
+func Mapping(path []Point, la, lb int) (matA, matB [][]int) {
+	matA, matB = make([][]int, la), make([][]int, lb)
+	for _, p := range path {
+		matA[p.A] = append(matA[p.A], p.B)
+		matB[p.B] = append(matB[p.B], p.A)
+	}
+	return matA, matB
+}
@@ -0,0 +1,170 @@
+package dtw
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+// checkPath checks the path is a valid warping path within w, and returns its cost.
+func checkPath(t *testing.T, name string, in Interface, w Window, path []Point) float64 {
+	la, lb := in.LenA(), in.LenB()
+	assert.Equal(t, name+" start", path[0], Point{0, 0})
+	assert.Equal(t, name+" end", path[len(path)-1], Point{la - 1, lb - 1})
+	cost := 0.0
+	for k, p := range path {
+		if w != nil {
+			assert.True(t, fmt.Sprintf("%s %v in window", name, p), w.Allows(p.A, p.B, la, lb))
+		}
+		if k > 0 {
+			dA, dB := p.A-path[k-1].A, p.B-path[k-1].B
+			assert.True(t, fmt.Sprintf("%s step %v -> %v", name, path[k-1], p), (dA == 0 || dA == 1) && (dB == 0 || dB == 1) && dA+dB > 0)
+		}
+		cost += in.CostOfMatch(p.A, p.B)
+	}
+	return cost
+}
+
+func TestDistance(t *testing.T) {
+	test := func(a, b []float64, w Window, exp float64) {
+		name := fmt.Sprintf("Distance(%v, %v)", a, b)
+		in := Series(a, b)
+		assert.Equal(t, name, Distance(in, w), exp)
+		dist, path := DistanceFull(in, w)
+		assert.Equal(t, name+" full", dist, exp)
+		if !math.IsInf(exp, 1) {
+			assert.Equal(t, name+" path cost", checkPath(t, name, in, w, path), exp)
+		}
+	}
+
+	test([]float64{1, 2, 3}, []float64{1, 2, 3}, nil, 0)
+	test([]float64{1, 2, 3}, []float64{1, 1, 2, 2, 3, 3}, nil, 0)
+	test([]float64{0, 1, 2}, []float64{0, 0, 0, 1, 2}, nil, 0)
+	test([]float64{1, 2, 3}, []float64{2, 3, 4}, nil, 2)
+	test([]float64{1}, []float64{2, 3}, nil, 3)
+	test([]float64{}, []float64{1}, nil, math.Inf(1))
+
+	// without warping, both 0s are matched with 2s
+	test([]float64{0, 0, 2, 2}, []float64{2, 2, 2, 2}, SakoeChiba(0), 4)
+	test([]float64{0, 0, 2, 2}, []float64{0, 2, 2, 2}, SakoeChiba(1), 0)
+	// the first three 0s cannot all be matched with the first 0 of b
+	test([]float64{0, 0, 0, 1, 2}, []float64{0, 1, 2}, Itakura(2), 2)
+	test([]float64{0, 0, 0, 1, 2}, []float64{0, 1, 2}, nil, 0)
+	// a series 3 times as long is out of the parallelogram of slope 2
+	test([]float64{0, 0, 0, 0, 0, 0, 0}, []float64{0, 0, 0}, Itakura(3), 0)
+	test([]float64{0, 0, 0, 0, 0, 0, 0}, []float64{0, 0, 0}, Itakura(2), math.Inf(1))
+}
+
+// naiveDistance computes the DP on all cells, skipping those not allowed by w.
+func naiveDistance(in Interface, w Window) float64 {
+	la, lb := in.LenA(), in.LenB()
+	if la == 0 || lb == 0 {
+		return math.Inf(1)
+	}
+	f := make([][]float64, la+1)
+	for iA := range f {
+		f[iA] = make([]float64, lb+1)
+		for iB := range f[iA] {
+			f[iA][iB] = math.Inf(1)
+		}
+	}
+	f[0][0] = 0
+	for iA := 1; iA <= la; iA++ {
+		for iB := 1; iB <= lb; iB++ {
+			if !w.Allows(iA-1, iB-1, la, lb) {
+				continue
+			}
+			mn := math.Min(math.Min(f[iA-1][iB-1], f[iA-1][iB]), f[iA][iB-1])
+			if iA == 1 && iB == 1 {
+				mn = 0
+			}
+			f[iA][iB] = mn + in.CostOfMatch(iA-1, iB-1)
+		}
+	}
+	return f[la][lb]
+}
+
+func TestWindow(t *testing.T) {
+	test := func(w Window, la, lb int, exp [][2]int) {
+		var act [][2]int
+		for iA := 0; iA < la; iA++ {
+			lo, hi := w.rowRange(iA, la, lb)
+			act = append(act, [2]int{lo, hi})
+		}
+		assert.StringEqual(t, fmt.Sprintf("ranges(%d, %d)", la, lb), act, exp)
+	}
+
+	test(nil, 2, 3, [][2]int{{0, 2}, {0, 2}})
+	test(SakoeChiba(1), 4, 4, [][2]int{{0, 1}, {0, 2}, {1, 3}, {2, 3}})
+	test(SakoeChiba(0), 3, 5, [][2]int{{0, 0}, {2, 2}, {4, 4}})
+	test(Itakura(2), 5, 5, [][2]int{{0, 0}, {1, 2}, {1, 3}, {2, 3}, {4, 4}})
+	assert.True(t, "Allows", SakoeChiba(1).Allows(1, 2, 4, 4))
+	assert.False(t, "Allows", SakoeChiba(1).Allows(0, 2, 4, 4))
+}
+
+func TestDistance_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() []float64 {
+		s := make([]float64, 1+rnd.Intn(20))
+		for i := range s {
+			s[i] = float64(rnd.Intn(10))
+		}
+		return s
+	}
+	for k := 0; k < 200; k++ {
+		a, b := gen(), gen()
+		for _, w := range []Window{nil, SakoeChiba(2), SakoeChiba(0), Itakura(3), Itakura(1.5)} {
+			in := Series(a, b)
+			d := Distance(in, w)
+			dist, path := DistanceFull(in, w)
+			assert.Equal(t, fmt.Sprintf("Distance(%v, %v)", a, b), dist, d)
+			assert.Equal(t, fmt.Sprintf("naiveDistance(%v, %v)", a, b), naiveDistance(in, w), d)
+			if !math.IsInf(d, 1) {
+				assert.Equal(t, "path cost", checkPath(t, "random", in, w, path), d)
+			}
+		}
+		// a window never decreases the distance
+		assert.True(t, "band", Distance(Series(a, b), SakoeChiba(2)) >= Distance(Series(a, b), nil))
+	}
+}
+
+func TestPoints(t *testing.T) {
+	a := [][]float64{{0, 0}, {1, 1}, {2, 2}}
+	b := [][]float64{{0, 0}, {0, 0}, {1, 1}, {2, 2}, {2, 3}}
+	dist, path := DistanceFull(Points(a, b), nil)
+	assert.Equal(t, "dist", dist, 1.0)
+	assert.StringEqual(t, "path", path, []Point{{0, 0}, {0, 1}, {1, 2}, {2, 3}, {2, 4}})
+
+	matA, matB := Mapping(path, len(a), len(b))
+	assert.StringEqual(t, "matA", matA, [][]int{{0, 1}, {2}, {3, 4}})
+	assert.StringEqual(t, "matB", matB, [][]int{{0}, {0}, {1}, {2}, {2}})
+}
+
+type countingSeries struct {
+	Interface
+	calls int
+}
+
+func (c *countingSeries) CostOfMatch(iA, iB int) float64 {
+	c.calls++
+	return c.Interface.CostOfMatch(iA, iB)
+}
+
+func TestDistance_WindowCells(t *testing.T) {
+	a := make([]float64, 1000)
+	for i := range a {
+		a[i] = float64(i % 7)
+	}
+	in := &countingSeries{Interface: Series(a, a)}
+	assert.Equal(t, "Distance", Distance(in, SakoeChiba(1)), 0.)
+	// only cells within the band are computed
+	assert.Equal(t, "calls", in.calls, 3*1000-2)
+
+	in.calls = 0
+	dist, _ := DistanceFull(in, SakoeChiba(1))
+	assert.Equal(t, "DistanceFull", dist, 0.)
+	assert.Equal(t, "calls", in.calls, 3*1000-2)
+}
@@ -0,0 +1,53 @@
+package ed
+
+/*
+StringBounded calculates the edit-distance between two strings as String does, if it is at most k, or returns k+1 otherwise, where k is non-negative. Only cells within k of the diagonal are computed, and it stops as soon as a whole row exceeds k. Input strings must be UTF-8 encoded.
+
+The time complexity is O(k min(m, n)) where m and n are lengths of a and b, and space complexity is O(n).
+*/
+func StringBounded(a, b string, k int) int {
+	ra, rb := []rune(a), []rune(b)
+	la, lb := len(ra), len(rb)
+	out := k + 1
+	if la-lb > k || lb-la > k {
+		return out
+	}
+	if la == 0 || lb == 0 {
+		return la + lb
+	}
+
+	// f is the row i, f1 is the row i-1; cells out of the band are out
+	f1, f := make([]int, lb+1), make([]int, lb+1)
+	for j := range f {
+		f[j] = min(j, out)
+	}
+	for i := 1; i <= la; i++ {
+		f1, f = f, f1
+		lo, hi := i-k, i+k
+		if lo < 1 {
+			lo = 1
+		}
+		if hi > lb {
+			hi = lb
+		}
+		f[0] = min(i, out)
+		if lo > 1 {
+			f[lo-1] = out
+		}
+		rowMin := f[lo-1]
+		for j := lo; j <= hi; j++ {
+			mn := min(f1[j]+1, f[j-1]+1) // delete & insert
+			mn = min(mn, f1[j-1]+Ternary(ra[i-1] == rb[j-1], 0, 1))
+			f[j] = min(mn, out)
+			rowMin = min(rowMin, f[j])
+		}
+		if hi < lb {
+			f[hi+1] = out
+		}
+		if rowMin > k {
+			return out
+		}
+	}
+
+	return f[lb]
+}
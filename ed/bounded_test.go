@@ -0,0 +1,44 @@
+package ed
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestStringBounded(t *testing.T) {
+	test := func(a, b string, k, dist int) {
+		assert.Equal(t, fmt.Sprintf("StringBounded(%s, %s, %d)", a, b, k), StringBounded(a, b, k), dist)
+	}
+
+	test("kitten", "sitting", 3, 3)
+	test("kitten", "sitting", 5, 3)
+	test("kitten", "sitting", 2, 3)
+	test("kitten", "sitting", 0, 1)
+	test("abc", "abc", 0, 0)
+	test("", "abc", 3, 3)
+	test("", "abc", 2, 3)
+	test("abcdef", "ab", 3, 4)
+	test("中文字", "中字", 1, 1)
+}
+
+func TestStringBounded_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() string {
+		rs := make([]rune, rnd.Intn(12))
+		for i := range rs {
+			rs[i] = 'a' + rune(rnd.Intn(3))
+		}
+		return string(rs)
+	}
+	for n := 0; n < 2000; n++ {
+		a, b, k := gen(), gen(), rnd.Intn(8)
+		exp := String(a, b)
+		if exp > k {
+			exp = k + 1
+		}
+		assert.Equal(t, fmt.Sprintf("StringBounded(%s, %s, %d)", a, b, k), StringBounded(a, b, k), exp)
+	}
+}
@@ -0,0 +1,251 @@
+/*
+costs package provides cost models of editing strings for typical typing and OCR errors, to be used with ed.EditDistanceF and ed.Interface.
+
+Costs are integers in units of Unit, so that discounts like a half edit can be expressed:
+
+	m := costs.NewModel()
+	m.Layout, m.AdjacentCost = costs.QWERTY, costs.Unit/2
+	m.CaseCost = costs.Unit / 5
+	d := ed.EditDistance(m.Interface("hello", "jello")) // 5
+
+Multi-rune confusions like "rn" read as "m" cannot be expressed by ed.Interface, where each rune is edited separately. Use Model.Distance for them:
+
+	m := costs.NewModel()
+	m.Confusions = costs.OCR
+	d := m.Distance("modern", "modem") // costs.OCRCost
+*/
+package costs
+
+import (
+	"unicode"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Unit is the cost of an ordinary edit in models of this package.
+const Unit = 10
+
+// DefaultAdjacentCost is the cost of changing a rune to an adjacent key if Model.AdjacentCost is zero.
+const DefaultAdjacentCost = Unit / 2
+
+// Layout is a keyboard layout, in which keys are adjacent if they are next to each other in a row, or touch each other in neighboring rows.
+type Layout struct {
+	Name string
+	// position of a key in row and column
+	pos map[rune][2]int
+}
+
+/*
+NewLayout returns a Layout from rows of unshifted keys, from top to bottom. Each row is assumed to be shifted right by half a key from the row above it, so key (r, c) touches (r-1, c), (r-1, c+1), (r+1, c-1) and (r+1, c).
+*/
+func NewLayout(name string, rows ...string) *Layout {
+	l := &Layout{Name: name, pos: make(map[rune][2]int)}
+	for r, row := range rows {
+		for c, k := range []rune(row) {
+			l.pos[k] = [2]int{r, c}
+		}
+	}
+	return l
+}
+
+// Predefined keyboard layouts.
+var (
+	QWERTY = NewLayout("QWERTY", "1234567890-=", "qwertyuiop[]", "asdfghjkl;'", "zxcvbnm,./")
+	AZERTY = NewLayout("AZERTY", "&é\"'(-è_çà)=", "azertyuiop^$", "qsdfghjklmù*", "wxcvbn,;:!")
+	Dvorak = NewLayout("Dvorak", "1234567890[]", "',.pyfgcrl/=", "aoeuidhtns-", ";qjkxbmwvz")
+)
+
+// Adjacent returns true if the keys of a and b are adjacent. Letters are compared case-insensitively.
+func (l *Layout) Adjacent(a, b rune) bool {
+	pa, okA := l.pos[unicode.ToLower(a)]
+	pb, okB := l.pos[unicode.ToLower(b)]
+	if !okA || !okB {
+		return false
+	}
+
+	dr, dc := pb[0]-pa[0], pb[1]-pa[1]
+	switch dr {
+	case 0:
+		return dc == 1 || dc == -1
+	case -1:
+		return dc == 0 || dc == 1
+	case 1:
+		return dc == 0 || dc == -1
+	}
+	return false
+}
+
+// Confusion is a pair of strings which are easily confused, with the cost of changing one to the other, in either direction.
+type Confusion struct {
+	A, B string
+	Cost int
+}
+
+// OCRCost is the cost of confusions in OCR.
+const OCRCost = Unit * 3 / 10
+
+// OCR is a table of common confusions of optical character recognition.
+var OCR = []Confusion{
+	{"rn", "m", OCRCost},
+	{"cl", "d", OCRCost},
+	{"vv", "w", OCRCost},
+	{"ri", "n", OCRCost},
+	{"li", "h", OCRCost},
+	{"0", "O", OCRCost},
+	{"0", "o", OCRCost},
+	{"O", "o", OCRCost},
+	{"1", "l", OCRCost},
+	{"1", "I", OCRCost},
+	{"l", "I", OCRCost},
+	{"5", "S", OCRCost},
+	{"8", "B", OCRCost},
+	{"2", "Z", OCRCost},
+	{"6", "b", OCRCost},
+	{"9", "g", OCRCost},
+	{"c", "e", OCRCost},
+	{"u", "v", OCRCost},
+	{"n", "h", OCRCost},
+}
+
+/*
+Model defines the costs of editing runes. The cost of changing a rune to a different one is the minimum of SubCost and the applicable discounts: CaseCost if they differ only in case, AdjacentCost if they are adjacent keys in Layout, and the Cost of a single-rune Confusion.
+*/
+type Model struct {
+	// Costs of an ordinary change, deletion and insertion.
+	SubCost, DelCost, InsCost int
+
+	// CaseCost is the cost of a case-only change, e.g. 'a' to 'A'. Ignored if zero.
+	CaseCost int
+
+	// Layout, if not nil, is the keyboard layout for AdjacentCost.
+	Layout *Layout
+	// AdjacentCost is the cost of changing a rune to an adjacent key. DefaultAdjacentCost is used if zero.
+	AdjacentCost int
+
+	// Confusions are pairs of easily confused strings. Single-rune ones apply to all functions, while multi-rune ones apply to Distance only.
+	Confusions []Confusion
+}
+
+// NewModel returns a Model with Unit costs and no discounts.
+func NewModel() *Model {
+	return &Model{SubCost: Unit, DelCost: Unit, InsCost: Unit}
+}
+
+// Sub returns the cost of changing a to b. It is zero if a == b.
+func (m *Model) Sub(a, b rune) int {
+	if a == b {
+		return 0
+	}
+
+	cost := m.SubCost
+	if m.CaseCost > 0 && m.CaseCost < cost && unicode.ToLower(a) == unicode.ToLower(b) {
+		cost = m.CaseCost
+	}
+	if m.Layout != nil {
+		adj := m.AdjacentCost
+		if adj == 0 {
+			adj = DefaultAdjacentCost
+		}
+		if adj < cost && m.Layout.Adjacent(a, b) {
+			cost = adj
+		}
+	}
+	for _, c := range m.Confusions {
+		if c.Cost < cost && (c.A == string(a) && c.B == string(b) || c.A == string(b) && c.B == string(a)) {
+			cost = c.Cost
+		}
+	}
+	return cost
+}
+
+// Funcs returns the cost functions of runes a and b for ed.EditDistanceF and ed.EditDistanceFFull.
+func (m *Model) Funcs(a, b []rune) (costOfChange func(iA, iB int) int, costOfDel func(iA int) int, costOfIns func(iB int) int) {
+	return func(iA, iB int) int {
+		return m.Sub(a[iA], b[iB])
+	}, ed.ConstCost(m.DelCost), ed.ConstCost(m.InsCost)
+}
+
+type runes struct {
+	ed.Base
+	m    *Model
+	a, b []rune
+}
+
+// Interface.CostOfChange
+func (in *runes) CostOfChange(iA, iB int) int {
+	return in.m.Sub(in.a[iA], in.b[iB])
+}
+
+// Interface.CostOfDel
+func (in *runes) CostOfDel(iA int) int {
+	return in.m.DelCost
+}
+
+// Interface.CostOfIns
+func (in *runes) CostOfIns(iB int) int {
+	return in.m.InsCost
+}
+
+// Interface returns an ed.Interface of the runes of strings a and b with costs of the model.
+func (m *Model) Interface(a, b string) ed.Interface {
+	ra, rb := []rune(a), []rune(b)
+	return &runes{Base: ed.Base{LA: len(ra), LB: len(rb)}, m: m, a: ra, b: rb}
+}
+
+/*
+Distance returns the edit-distance between strings a and b with the model, including multi-rune Confusions, e.g. changing "rn" to "m" as a whole.
+
+The time complexity is O(mnk) where m and n are lengths of a and b, and k is the number of multi-rune confusions. The space complexity is O(mn) if there are multi-rune confusions, O(n) otherwise.
+*/
+func (m *Model) Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// multi-rune confusions in both directions
+	type multi struct {
+		a, b []rune
+		cost int
+	}
+	var multis []multi
+	for _, c := range m.Confusions {
+		ca, cb := []rune(c.A), []rune(c.B)
+		if len(ca) <= 1 && len(cb) <= 1 {
+			continue
+		}
+		multis = append(multis, multi{ca, cb, c.Cost}, multi{cb, ca, c.Cost})
+	}
+	if len(multis) == 0 {
+		costOfChange, costOfDel, costOfIns := m.Funcs(ra, rb)
+		return ed.EditDistanceF(len(ra), len(rb), costOfChange, costOfDel, costOfIns)
+	}
+
+	// f[i][j] is the distance between ra[:i] and rb[:j]
+	f := make([][]int, len(ra)+1)
+	for i := range f {
+		f[i] = make([]int, len(rb)+1)
+	}
+	for j := 1; j <= len(rb); j++ {
+		f[0][j] = f[0][j-1] + m.InsCost
+	}
+	for i := 1; i <= len(ra); i++ {
+		f[i][0] = f[i-1][0] + m.DelCost
+		for j := 1; j <= len(rb); j++ {
+			mn := f[i-1][j-1] + m.Sub(ra[i-1], rb[j-1])
+			if c := f[i-1][j] + m.DelCost; c < mn {
+				mn = c
+			}
+			if c := f[i][j-1] + m.InsCost; c < mn {
+				mn = c
+			}
+			for _, mt := range multis {
+				la, lb := len(mt.a), len(mt.b)
+				if la <= i && lb <= j && string(ra[i-la:i]) == string(mt.a) && string(rb[j-lb:j]) == string(mt.b) {
+					if c := f[i-la][j-lb] + mt.cost; c < mn {
+						mn = c
+					}
+				}
+			}
+			f[i][j] = mn
+		}
+	}
+	return f[len(ra)][len(rb)]
+}
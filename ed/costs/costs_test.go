@@ -0,0 +1,98 @@
+package costs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/daviddengcn/go-algs/ed"
+	"github.com/golangplus/testing/assert"
+)
+
+func TestAdjacent(t *testing.T) {
+	test := func(l *Layout, a, b rune, exp bool) {
+		assert.Equal(t, fmt.Sprintf("%s.Adjacent(%c, %c)", l.Name, a, b), l.Adjacent(a, b), exp)
+		assert.Equal(t, fmt.Sprintf("%s.Adjacent(%c, %c)", l.Name, b, a), l.Adjacent(b, a), exp)
+	}
+
+	test(QWERTY, 'f', 'g', true)
+	test(QWERTY, 'f', 'r', true)
+	test(QWERTY, 'f', 't', true)
+	test(QWERTY, 'f', 'e', false)
+	test(QWERTY, 'f', 'c', true)
+	test(QWERTY, 'f', 'v', true)
+	test(QWERTY, 'f', 'b', false)
+	test(QWERTY, 'q', 'A', true)
+	test(QWERTY, 'f', 'f', false)
+	test(QWERTY, 'f', '?', false)
+	test(AZERTY, 'a', 'z', true)
+	test(AZERTY, 'a', 'q', true)
+	test(AZERTY, 'q', 'w', true)
+	test(Dvorak, 'a', 'o', true)
+	test(Dvorak, 'a', 's', false)
+	test(Dvorak, 'h', 't', true)
+}
+
+func TestModel(t *testing.T) {
+	m := NewModel()
+	test := func(a, b string, exp int) {
+		assert.Equal(t, fmt.Sprintf("EditDistance(%s, %s)", a, b), ed.EditDistance(m.Interface(a, b)), exp)
+		ra, rb := []rune(a), []rune(b)
+		costOfChange, costOfDel, costOfIns := m.Funcs(ra, rb)
+		assert.Equal(t, fmt.Sprintf("EditDistanceF(%s, %s)", a, b), ed.EditDistanceF(len(ra), len(rb), costOfChange, costOfDel, costOfIns), exp)
+		assert.Equal(t, fmt.Sprintf("Distance(%s, %s)", a, b), m.Distance(a, b), exp)
+	}
+
+	test("hello", "jello", Unit)
+	test("hello", "Hello", Unit)
+	test("abc", "", 3*Unit)
+
+	m.Layout, m.AdjacentCost = QWERTY, Unit/2
+	m.CaseCost = Unit / 5
+	test("hello", "jello", Unit/2)
+	test("hello", "Jello", Unit/2)
+	test("hello", "Hello", Unit/5)
+	test("hello", "yello", Unit/2)
+	test("hello", "pello", Unit)
+
+	// a zero AdjacentCost means DefaultAdjacentCost
+	m = NewModel()
+	m.Layout = QWERTY
+	test("hello", "jello", DefaultAdjacentCost)
+	test("hello", "pello", Unit)
+	m.AdjacentCost = 3
+	test("hello", "jello", 3)
+
+	m = NewModel()
+	m.Confusions = OCR
+	test("l0", "IO", 2*OCRCost)
+	test("cat", "eat", OCRCost)
+}
+
+func TestDistance_Multi(t *testing.T) {
+	m := NewModel()
+	m.Confusions = OCR
+	test := func(a, b string, exp int) {
+		assert.Equal(t, fmt.Sprintf("Distance(%s, %s)", a, b), m.Distance(a, b), exp)
+	}
+
+	test("modern", "modem", OCRCost)
+	test("modem", "modern", OCRCost)
+	test("clear", "dear", OCRCost)
+	test("burn", "bum", OCRCost)
+	test("rnrn", "mm", 2*OCRCost)
+	test("rn", "rn", 0)
+	test("", "m", Unit)
+	test("modern", "mode", 2*Unit)
+}
+
+func Example() {
+	m := NewModel()
+	m.Layout, m.AdjacentCost = QWERTY, Unit/2
+	fmt.Println(ed.EditDistance(m.Interface("hello", "jello")))
+
+	m.Confusions = OCR
+	fmt.Println(m.Distance("modern", "modem"))
+	// Output:
+	// 5
+	// 3
+}
@@ -0,0 +1,265 @@
+package ed
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrBudgetExceeded is returned by the Ctx functions if the number of cells of the DP table exceeds the budget set by WithMaxCells.
+var ErrBudgetExceeded = errors.New("ed: budget of DP cells exceeded")
+
+type maxCellsKey struct{}
+
+/*
+WithMaxCells returns a copy of ctx with a budget of DP cells. The Ctx functions return ErrBudgetExceeded without computing anything if the product of the lengths of the two lists exceeds maxCells.
+*/
+func WithMaxCells(ctx context.Context, maxCells int64) context.Context {
+	return context.WithValue(ctx, maxCellsKey{}, maxCells)
+}
+
+// checkBudget returns ErrBudgetExceeded if la*lb exceeds the budget in ctx, or ctx.Err() if ctx is already done.
+func checkBudget(ctx context.Context, la, lb int) error {
+	maxCells, ok := ctx.Value(maxCellsKey{}).(int64)
+	if !ok {
+		return ctx.Err()
+	}
+	if la > 0 && int64(lb) > maxCells/int64(la) {
+		return ErrBudgetExceeded
+	}
+
+	return ctx.Err()
+}
+
+// checkedCosts wraps an Interface and records the first negative cost.
+type checkedCosts struct {
+	in  Interface
+	err error
+}
+
+func (c *checkedCosts) change(iA, iB int) int {
+	cost := c.in.CostOfChange(iA, iB)
+	if cost < 0 && c.err == nil {
+		c.err = &NegativeCostError{Op: "change", IA: iA, IB: iB, Cost: cost}
+	}
+	return cost
+}
+
+func (c *checkedCosts) del(iA int) int {
+	cost := c.in.CostOfDel(iA)
+	if cost < 0 && c.err == nil {
+		c.err = &NegativeCostError{Op: "delete", IA: iA, IB: -1, Cost: cost}
+	}
+	return cost
+}
+
+func (c *checkedCosts) ins(iB int) int {
+	cost := c.in.CostOfIns(iB)
+	if cost < 0 && c.err == nil {
+		c.err = &NegativeCostError{Op: "insert", IA: -1, IB: iB, Cost: cost}
+	}
+	return cost
+}
+
+/*
+StringCtx is similar to String but checks ctx for each rune of a. ctx.Err() is returned if ctx is done before finishing, and ErrBudgetExceeded is returned if the budget set by WithMaxCells is exceeded.
+*/
+func StringCtx(ctx context.Context, a, b string) (int, error) {
+	if err := checkBudget(ctx, utf8.RuneCountInString(a), utf8.RuneCountInString(b)); err != nil {
+		return 0, err
+	}
+
+	f := make([]int, utf8.RuneCountInString(b)+1)
+
+	for j := range f {
+		f[j] = j
+	}
+
+	for _, ca := range a {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		j := 1
+		fj1 := f[0] // fj1 is the value of f[j - 1] in last iteration
+		f[0]++
+		for _, cb := range b {
+			mn := min(f[j]+1, f[j-1]+1) // delete & insert
+			if cb != ca {
+				mn = min(mn, fj1+1) // change
+			} else {
+				mn = min(mn, fj1) // matched
+			}
+
+			fj1, f[j] = f[j], mn // save f[j] to fj1(j is about to increase), update f[j] to mn
+			j++
+		}
+	}
+
+	return f[len(f)-1], nil
+}
+
+/*
+EditDistanceCtx is similar to EditDistance but checks ctx for each row of the DP table. ctx.Err() is returned if ctx is done before finishing, and ErrBudgetExceeded is returned if the budget set by WithMaxCells is exceeded.
+
+A *NegativeCostError is returned if a negative cost is found, and ErrNoAlignment is returned if the distance is Infinite.
+*/
+func EditDistanceCtx(ctx context.Context, in Interface) (int, error) {
+	la, lb := in.LenA(), in.LenB()
+	c := &checkedCosts{in: in}
+	if err := checkBudget(ctx, la, lb); err != nil {
+		return 0, err
+	}
+
+	f := make([]int, lb+1)
+
+	for j := 1; j <= lb; j++ {
+		f[j] = addCost(f[j-1], c.ins(j-1))
+	}
+
+	for i := 0; i < la; i++ {
+		if c.err != nil {
+			return 0, c.err
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		fj1 := f[0] // fj1 is the value of f[j - 1] in last iteration
+		f[0] = addCost(f[0], c.del(i))
+		for j := 1; j <= lb; j++ {
+			mn := min(addCost(f[j], c.del(i)), addCost(f[j-1], c.ins(j-1))) // delete & insert
+			mn = min(mn, addCost(fj1, c.change(i, j-1)))                    // change/matched
+
+			fj1, f[j] = f[j], mn // save f[j] to fj1(j is about to increase), update f[j] to mn
+		}
+	}
+
+	if c.err != nil {
+		return 0, c.err
+	}
+	if f[lb] == Infinite {
+		return 0, ErrNoAlignment
+	}
+
+	return f[lb], nil
+}
+
+/*
+EditDistanceFullCtx is similar to EditDistanceFull but checks ctx for each row of the DP table. ctx.Err() is returned if ctx is done before finishing, and ErrBudgetExceeded is returned if the budget set by WithMaxCells is exceeded.
+
+A *NegativeCostError is returned if a negative cost is found, and ErrNoAlignment is returned if the distance is Infinite.
+*/
+func EditDistanceFullCtx(ctx context.Context, in Interface) (dist int, matA, matB []int, err error) {
+	la, lb := in.LenA(), in.LenB()
+	c := &checkedCosts{in: in}
+	if err := checkBudget(ctx, la, lb); err != nil {
+		return 0, nil, nil, err
+	}
+
+	f := make([]int, lb+1)
+	ops := make([]byte, la*lb)
+
+	for j := 1; j <= lb; j++ {
+		f[j] = addCost(f[j-1], c.ins(j-1))
+	}
+
+	// Matching with dynamic programming
+	p := 0
+	for i := 0; i < la; i++ {
+		if c.err != nil {
+			return 0, nil, nil, c.err
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, nil, nil, err
+		}
+
+		fj1 := f[0] // fj1 is the value of f[j - 1] in last iteration
+		f[0] = addCost(f[0], c.del(i))
+		for j := 1; j <= lb; j++ {
+			mn, op := addCost(f[j], c.del(i)), opDEL // delete
+
+			if v := addCost(f[j-1], c.ins(j-1)); v < mn {
+				// insert
+				mn, op = v, opINS
+			}
+
+			// change/matched
+			if v := addCost(fj1, c.change(i, j-1)); v < mn {
+				mn, op = v, opCHANGE
+			}
+
+			fj1, f[j], ops[p] = f[j], mn, op // save f[j] to fj1(j is about to increase), update f[j] to mn
+			p++
+		}
+	}
+	if c.err != nil {
+		return 0, nil, nil, c.err
+	}
+	if f[lb] == Infinite {
+		return 0, nil, nil, ErrNoAlignment
+	}
+	// Reversely find the match info
+	matA, matB = matchingFromOps(la, lb, ops)
+
+	return f[lb], matA, matB, nil
+}
+
+// funcs is an Interface defined by lengths and cost functions.
+type funcs struct {
+	la, lb int
+	costFuncs
+}
+
+// Interface.LenA
+func (f *funcs) LenA() int {
+	return f.la
+}
+
+// Interface.LenB
+func (f *funcs) LenB() int {
+	return f.lb
+}
+
+// Interface.CostOfChange
+func (f *funcs) CostOfChange(iA, iB int) int {
+	return f.change(iA, iB)
+}
+
+// Interface.CostOfDel
+func (f *funcs) CostOfDel(iA int) int {
+	return f.del(iA)
+}
+
+// Interface.CostOfIns
+func (f *funcs) CostOfIns(iB int) int {
+	return f.ins(iB)
+}
+
+/*
+EditDistanceChecked is similar to EditDistance but checks the costs: a *NegativeCostError is returned if a negative cost is found, and ErrNoAlignment is returned if the distance is Infinite. The common prefix and suffix are not skipped even if in implements Equaler.
+*/
+func EditDistanceChecked(in Interface) (int, error) {
+	return EditDistanceCtx(context.Background(), in)
+}
+
+/*
+EditDistanceFullChecked is similar to EditDistanceFull but checks the costs as EditDistanceChecked does.
+*/
+func EditDistanceFullChecked(in Interface) (dist int, matA, matB []int, err error) {
+	return EditDistanceFullCtx(context.Background(), in)
+}
+
+/*
+EditDistanceFChecked is similar to EditDistanceF but checks the costs as EditDistanceChecked does.
+*/
+func EditDistanceFChecked(lenA, lenB int, costOfChange func(iA, iB int) int, costOfDel func(iA int) int, costOfIns func(iB int) int) (int, error) {
+	return EditDistanceCtx(context.Background(), &funcs{lenA, lenB, costFuncs{costOfChange, costOfDel, costOfIns}})
+}
+
+/*
+EditDistanceFFullChecked is similar to EditDistanceFFull but checks the costs as EditDistanceChecked does.
+*/
+func EditDistanceFFullChecked(lenA, lenB int, costOfChange func(iA, iB int) int, costOfDel func(iA int) int, costOfIns func(iB int) int) (dist int, matA, matB []int, err error) {
+	return EditDistanceFullCtx(context.Background(), &funcs{lenA, lenB, costFuncs{costOfChange, costOfDel, costOfIns}})
+}
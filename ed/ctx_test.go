@@ -0,0 +1,142 @@
+package ed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestStringCtx(t *testing.T) {
+	test := func(a, b string, d int) {
+		actD, err := StringCtx(context.Background(), a, b)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("Edit-distance between %s and %s", a, b), actD, d)
+	}
+
+	test("abcd", "bcde", 2)
+	test("abcde", "", 5)
+	test("", "abcde", 5)
+	test("", "", 0)
+	test("abcde", "dabce", 2)
+}
+
+func TestEditDistanceCtx(t *testing.T) {
+	test := func(a, b string, d int, matA, matB []int) {
+		in := &stringInterface{[]rune(a), []rune(b)}
+		actD, err := EditDistanceCtx(context.Background(), in)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("Edit-distance between %s and %s", a, b), actD, d)
+
+		actD, actMatA, actMatB, err := EditDistanceFullCtx(context.Background(), in)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("Edit-distance between %s and %s", a, b), actD, d)
+		assert.StringEqual(t, fmt.Sprintf("matA for matchting between %s and %s", a, b), actMatA, matA)
+		assert.StringEqual(t, fmt.Sprintf("matB for matchting between %s and %s", a, b), actMatB, matB)
+	}
+
+	test("abcd", "bcde", 213, []int{-1, 0, 1, 2}, []int{1, 2, 3, -1})
+	test("", "", 0, []int{}, []int{})
+	test("abcde", "dabce", 213, []int{1, 2, 3, -1, 4}, []int{-1, 0, 1, 2, 4})
+}
+
+func TestCtx_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := StringCtx(ctx, "abc", "abd")
+	assert.Equal(t, "err", err, context.Canceled)
+	_, err = EditDistanceCtx(ctx, &stringInterface{[]rune("abc"), []rune("abd")})
+	assert.Equal(t, "err", err, context.Canceled)
+	_, _, _, err = EditDistanceFullCtx(ctx, &stringInterface{[]rune("abc"), []rune("abd")})
+	assert.Equal(t, "err", err, context.Canceled)
+}
+
+func TestCtx_Budget(t *testing.T) {
+	ctx := WithMaxCells(context.Background(), 9)
+
+	d, err := StringCtx(ctx, "abc", "abd")
+	assert.NoError(t, err)
+	assert.Equal(t, "d", d, 1)
+
+	_, err = StringCtx(ctx, "abcd", "abd")
+	assert.Equal(t, "err", err, ErrBudgetExceeded)
+	_, err = EditDistanceCtx(ctx, &stringInterface{[]rune("abcd"), []rune("abd")})
+	assert.Equal(t, "err", err, ErrBudgetExceeded)
+	_, _, _, err = EditDistanceFullCtx(ctx, &stringInterface{[]rune("abcd"), []rune("abd")})
+	assert.Equal(t, "err", err, ErrBudgetExceeded)
+}
+
+func TestCtx_NegativeCost(t *testing.T) {
+	in := &funcInterface{2, 2, func(iA, iB int) int {
+		return Ternary(iA == 1 && iB == 0, -1, 1)
+	}, ConstCost(1), ConstCost(1)}
+	_, err := EditDistanceCtx(context.Background(), in)
+	var negErr *NegativeCostError
+	assert.True(t, "errors.As(err, &negErr)", errors.As(err, &negErr))
+	assert.Equal(t, "negErr", *negErr, NegativeCostError{Op: "change", IA: 1, IB: 0, Cost: -1})
+	assert.Equal(t, "err.Error()", err.Error(), "ed: negative cost -1 of changing A[1] to B[0]")
+
+	in.costOfIns = func(iB int) int {
+		return -2
+	}
+	_, _, _, err = EditDistanceFullCtx(context.Background(), in)
+	assert.Equal(t, "err.Error()", err.Error(), "ed: negative cost -2 of inserting B[0]")
+}
+
+func TestCtx_NoAlignment(t *testing.T) {
+	a, b := "ab", "ac"
+	in := &funcInterface{len(a), len(b), func(iA, iB int) int {
+		return Ternary(a[iA] == b[iB], 0, Infinite)
+	}, ConstCost(Infinite), ConstCost(Infinite)}
+	_, err := EditDistanceCtx(context.Background(), in)
+	assert.Equal(t, "err", err, ErrNoAlignment)
+	_, _, _, err = EditDistanceFullCtx(context.Background(), in)
+	assert.Equal(t, "err", err, ErrNoAlignment)
+}
+
+func TestCtx_CanceledEmpty(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := StringCtx(ctx, "", "")
+	assert.Equal(t, "err", err, context.Canceled)
+	_, err = EditDistanceCtx(ctx, &stringInterface{nil, nil})
+	assert.Equal(t, "err", err, context.Canceled)
+	_, _, _, err = EditDistanceFullCtx(WithMaxCells(ctx, 100), &stringInterface{nil, nil})
+	assert.Equal(t, "err", err, context.Canceled)
+}
+
+func TestChecked(t *testing.T) {
+	change := func(iA, iB int) int {
+		return Ternary(iA == 1 && iB == 0, -1, 1)
+	}
+	in := &funcInterface{2, 2, change, ConstCost(1), ConstCost(1)}
+	negErr := "ed: negative cost -1 of changing A[1] to B[0]"
+
+	_, err := EditDistanceChecked(in)
+	assert.Equal(t, "EditDistanceChecked", fmt.Sprint(err), negErr)
+	_, _, _, err = EditDistanceFullChecked(in)
+	assert.Equal(t, "EditDistanceFullChecked", fmt.Sprint(err), negErr)
+	_, err = EditDistanceFChecked(2, 2, change, ConstCost(1), ConstCost(1))
+	assert.Equal(t, "EditDistanceFChecked", fmt.Sprint(err), negErr)
+	_, _, _, err = EditDistanceFFullChecked(2, 2, change, ConstCost(1), ConstCost(1))
+	assert.Equal(t, "EditDistanceFFullChecked", fmt.Sprint(err), negErr)
+
+	_, err = EditDistanceFChecked(1, 1, func(iA, iB int) int { return Infinite }, ConstCost(Infinite), ConstCost(1))
+	assert.Equal(t, "no alignment", err, ErrNoAlignment)
+	_, _, _, err = EditDistanceFFullChecked(1, 1, func(iA, iB int) int { return Infinite }, ConstCost(Infinite), ConstCost(1))
+	assert.Equal(t, "no alignment", err, ErrNoAlignment)
+
+	d, matA, matB, err := EditDistanceFFullChecked(2, 2, func(iA, iB int) int { return 1 }, ConstCost(1), ConstCost(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "d", d, 2)
+	assert.StringEqual(t, "matA", matA, []int{0, 1})
+	assert.StringEqual(t, "matB", matB, []int{0, 1})
+	in2 := &stringInterface{[]rune("kitten"), []rune("sitting")}
+	d, err = EditDistanceChecked(in2)
+	assert.NoError(t, err)
+	assert.Equal(t, "d", d, EditDistance(in2))
+}
@@ -0,0 +1,340 @@
+/*
+diff package computes the difference between two lists of lines.
+
+An Algorithm returns match indexes in the same format as ed.EditDistanceFull: each element in matA and matB is the index of the matched line in the other list, if it is equal to or greater than zero; or -1 meaning a deleting or inserting in matA or matB, respectively. Matched lines are always equal, and the matching is monotonic, so the result can be consumed by any formatter for ed matching results.
+
+DP and Myers produce minimal edit scripts. Patience and Histogram are the heuristics used by git, which prefer aligning rare lines (e.g. function headers) over frequent ones (e.g. braces and blank lines), and usually produce more readable results for source code.
+*/
+package diff
+
+import (
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Algorithm is a function computing the matching between two lists of lines.
+type Algorithm func(a, b []string) (matA, matB []int)
+
+// Algorithms maps names to all algorithms defined in this package.
+var Algorithms = map[string]Algorithm{
+	"dp":        DP,
+	"myers":     Myers,
+	"patience":  Patience,
+	"histogram": Histogram,
+}
+
+func newMatching(la, lb int) (matA, matB []int) {
+	matA, matB = make([]int, la), make([]int, lb)
+	for i := range matA {
+		matA[i] = -1
+	}
+	for j := range matB {
+		matB[j] = -1
+	}
+
+	return matA, matB
+}
+
+/*
+DP returns the matching computed by ed.EditDistanceFFull with unit deleting/inserting costs. Unequal lines are never matched.
+
+The time and space complexity are all O(mn) where m and n are lengths of a and b.
+*/
+func DP(a, b []string) (matA, matB []int) {
+	_, matA, matB = ed.EditDistanceFFull(len(a), len(b), func(iA, iB int) int {
+		// A change costs the same as a deleting plus an inserting so that it is never preferred.
+		return ed.Ternary(a[iA] == b[iB], 0, 2)
+	}, ed.ConstCost(1), ed.ConstCost(1))
+
+	for i, j := range matA {
+		if j >= 0 && a[i] != b[j] {
+			matA[i], matB[j] = -1, -1
+		}
+	}
+
+	return matA, matB
+}
+
+/*
+Myers returns the matching computed by Myers' O(ND) difference algorithm, in the linear space variant dividing ranges at middle snakes.
+
+The time complexity is O((m+n)D) where m and n are lengths of a and b, and D is the size of the minimal edit script, and space complexity is O(m+n).
+*/
+func Myers(a, b []string) (matA, matB []int) {
+	matA, matB = newMatching(len(a), len(b))
+	myers(a, b, 0, len(a), 0, len(b), matA, matB)
+
+	return matA, matB
+}
+
+// myers matches a[aLo:aHi] with b[bLo:bHi] and saves the results into matA and matB.
+func myers(a, b []string, aLo, aHi, bLo, bHi int, matA, matB []int) {
+	aLo, aHi, bLo, bHi = matchEnds(a, b, aLo, aHi, bLo, bHi, matA, matB)
+	if aLo == aHi || bLo == bHi {
+		return
+	}
+
+	if x, y, ok := middleSnake(a, b, aLo, aHi, bLo, bHi); ok {
+		myers(a, b, aLo, x, bLo, y, matA, matB)
+		myers(a, b, x, aHi, y, bHi, matA, matB)
+	}
+}
+
+/*
+middleSnake returns a point (x, y) on an optimal path from (aLo, bLo) to (aHi, bHi), found where the furthest reaching paths of D/2 steps from both ends overlap. The ranges before and after the point have shorter edit scripts if the two ranges have no common prefix or suffix. ok is false if there are no common lines.
+*/
+func middleSnake(a, b []string, aLo, aHi, bLo, bHi int) (x, y int, ok bool) {
+	n, m := aHi-aLo, bHi-bLo
+	maxD := (n + m + 1) / 2
+	// vf[k+maxD] is the furthest x on diagonal k from the start, and vb[k+maxD] is that from the end, or -1 if not reached
+	vf, vb := make([]int, 2*maxD+2), make([]int, 2*maxD+2)
+	for k := range vf {
+		vf[k], vb[k] = -1, -1
+	}
+	vf[maxD+1], vb[maxD+1] = 0, 0
+	delta := n - m
+	// if delta is odd, the paths overlap in a forward step, otherwise in a backward one
+	odd := delta%2 != 0
+	// diagonals out of the ranges are skipped by the start and end shifts
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			var x int
+			if k == -d || k != d && vf[k-1+maxD] < vf[k+1+maxD] {
+				x = vf[k+1+maxD] // down: inserting
+			} else {
+				x = vf[k-1+maxD] + 1 // right: deleting
+			}
+			y := x - k
+			for x < n && y < m && a[aLo+x] == b[bLo+y] {
+				x++
+				y++
+			}
+			vf[k+maxD] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				if kb := delta - k + maxD; kb >= 0 && kb < len(vb) && vb[kb] >= 0 && x >= n-vb[kb] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			var x int
+			if k == -d || k != d && vb[k-1+maxD] < vb[k+1+maxD] {
+				x = vb[k+1+maxD]
+			} else {
+				x = vb[k-1+maxD] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[aHi-1-x] == b[bHi-1-y] {
+				x++
+				y++
+			}
+			vb[k+maxD] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !odd:
+				if kf := delta - k + maxD; kf >= 0 && kf < len(vf) && vf[kf] >= 0 && vf[kf] >= n-x {
+					xf := vf[kf]
+					return aLo + xf, bLo + xf - (kf - maxD), true
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+// matchEnds matches the common prefix and suffix of a[aLo:aHi] and b[bLo:bHi] and returns the remaining ranges.
+func matchEnds(a, b []string, aLo, aHi, bLo, bHi int, matA, matB []int) (int, int, int, int) {
+	for aLo < aHi && bLo < bHi && a[aLo] == b[bLo] {
+		matA[aLo], matB[bLo] = bLo, aLo
+		aLo++
+		bLo++
+	}
+	for aLo < aHi && bLo < bHi && a[aHi-1] == b[bHi-1] {
+		aHi--
+		bHi--
+		matA[aHi], matB[bHi] = bHi, aHi
+	}
+
+	return aLo, aHi, bLo, bHi
+}
+
+/*
+Patience returns the matching computed by the patience diff algorithm.
+
+Lines appearing exactly once in both ranges are used as anchors, the longest increasing subsequence of them is matched, and the gaps between anchors are processed recursively. Ranges without unique common lines fall back to Myers.
+*/
+func Patience(a, b []string) (matA, matB []int) {
+	matA, matB = newMatching(len(a), len(b))
+	patience(a, b, 0, len(a), 0, len(b), matA, matB)
+
+	return matA, matB
+}
+
+func patience(a, b []string, aLo, aHi, bLo, bHi int, matA, matB []int) {
+	aLo, aHi, bLo, bHi = matchEnds(a, b, aLo, aHi, bLo, bHi, matA, matB)
+	if aLo == aHi || bLo == bHi {
+		return
+	}
+
+	type occurrence struct {
+		cntA, cntB int
+		iB         int
+	}
+	occs := make(map[string]*occurrence)
+	for i := aLo; i < aHi; i++ {
+		o := occs[a[i]]
+		if o == nil {
+			o = &occurrence{}
+			occs[a[i]] = o
+		}
+		o.cntA++
+	}
+	for j := bLo; j < bHi; j++ {
+		if o := occs[b[j]]; o != nil {
+			o.cntB++
+			o.iB = j
+		}
+	}
+
+	// unique common lines, ordered by positions in a
+	var uniqs [][2]int
+	for i := aLo; i < aHi; i++ {
+		if o := occs[a[i]]; o.cntA == 1 && o.cntB == 1 {
+			uniqs = append(uniqs, [2]int{i, o.iB})
+		}
+	}
+	if len(uniqs) == 0 {
+		myers(a, b, aLo, aHi, bLo, bHi, matA, matB)
+		return
+	}
+
+	lis := longestIncreasing(uniqs)
+	i, j := aLo, bLo
+	for _, u := range lis {
+		patience(a, b, i, u[0], j, u[1], matA, matB)
+		matA[u[0]], matB[u[1]] = u[1], u[0]
+		i, j = u[0]+1, u[1]+1
+	}
+	patience(a, b, i, aHi, j, bHi, matA, matB)
+}
+
+// longestIncreasing returns the longest subsequence of pairs whose second elements are increasing. The first elements of pairs are assumed to be increasing.
+func longestIncreasing(pairs [][2]int) [][2]int {
+	// tails[l] is the index of the smallest tail of increasing subsequences of length l+1
+	tails := make([]int, 0, len(pairs))
+	prev := make([]int, len(pairs))
+	for p, pr := range pairs {
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			m := (lo + hi) / 2
+			if pairs[tails[m]][1] < pr[1] {
+				lo = m + 1
+			} else {
+				hi = m
+			}
+		}
+		prev[p] = -1
+		if lo > 0 {
+			prev[p] = tails[lo-1]
+		}
+		if lo == len(tails) {
+			tails = append(tails, p)
+		} else {
+			tails[lo] = p
+		}
+	}
+
+	res := make([][2]int, len(tails))
+	for p, l := tails[len(tails)-1], len(tails)-1; l >= 0; p, l = prev[p], l-1 {
+		res[l] = pairs[p]
+	}
+
+	return res
+}
+
+// maxChainLength is the maximum number of occurrences of a line considered by Histogram. The same as git.
+const maxChainLength = 64
+
+/*
+Histogram returns the matching computed by the histogram diff algorithm.
+
+For each range, the longest common region containing the line with the lowest number of occurrences in a is matched, and the ranges before and after it are processed recursively. Ranges without common lines, or with only frequent lines, fall back to Myers.
+*/
+func Histogram(a, b []string) (matA, matB []int) {
+	matA, matB = newMatching(len(a), len(b))
+	histogram(a, b, 0, len(a), 0, len(b), matA, matB)
+
+	return matA, matB
+}
+
+func histogram(a, b []string, aLo, aHi, bLo, bHi int, matA, matB []int) {
+	aLo, aHi, bLo, bHi = matchEnds(a, b, aLo, aHi, bLo, bHi, matA, matB)
+	if aLo == aHi || bLo == bHi {
+		return
+	}
+
+	// positions of lines in a
+	poss := make(map[string][]int)
+	for i := aLo; i < aHi; i++ {
+		poss[a[i]] = append(poss[a[i]], i)
+	}
+
+	found := false
+	bestCnt, bestA, bestB, bestLen := maxChainLength+1, 0, 0, 0
+	for j := bLo; j < bHi; {
+		nextJ := j + 1
+		ps := poss[b[j]]
+		if len(ps) == 0 || len(ps) > bestCnt {
+			j = nextJ
+			continue
+		}
+		for _, i := range ps {
+			// extend the region around (i, j)
+			s, t := i, j
+			for s > aLo && t > bLo && a[s-1] == b[t-1] {
+				s--
+				t--
+			}
+			e, f := i+1, j+1
+			for e < aHi && f < bHi && a[e] == b[f] {
+				e++
+				f++
+			}
+			// the count of a region is the lowest count of its lines
+			cnt := len(ps)
+			for k := s; k < e; k++ {
+				if c := len(poss[a[k]]); c < cnt {
+					cnt = c
+				}
+			}
+			if cnt < bestCnt || cnt == bestCnt && e-s > bestLen {
+				found = true
+				bestCnt, bestA, bestB, bestLen = cnt, s, t, e-s
+			}
+			if f > nextJ {
+				nextJ = f
+			}
+		}
+		j = nextJ
+	}
+
+	if !found {
+		myers(a, b, aLo, aHi, bLo, bHi, matA, matB)
+		return
+	}
+
+	histogram(a, b, aLo, bestA, bLo, bestB, matA, matB)
+	for k := 0; k < bestLen; k++ {
+		matA[bestA+k], matB[bestB+k] = bestB+k, bestA+k
+	}
+	histogram(a, b, bestA+bestLen, aHi, bestB+bestLen, bHi, matA, matB)
+}
@@ -0,0 +1,166 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+// checkMatching checks matA and matB are consistent, monotonic, and only match equal lines. The number of matched lines is returned.
+func checkMatching(t *testing.T, name string, a, b []string, matA, matB []int) int {
+	assert.Equal(t, name+" len(matA)", len(matA), len(a))
+	assert.Equal(t, name+" len(matB)", len(matB), len(b))
+	cnt, last := 0, -1
+	for i, j := range matA {
+		if j < 0 {
+			continue
+		}
+		cnt++
+		assert.True(t, fmt.Sprintf("%s matA[%d] = %d is monotonic", name, i, j), j > last)
+		assert.Equal(t, fmt.Sprintf("%s matB[matA[%d]]", name, i), matB[j], i)
+		assert.Equal(t, fmt.Sprintf("%s b[matA[%d]]", name, i), b[j], a[i])
+		last = j
+	}
+	for j, i := range matB {
+		if i >= 0 {
+			assert.Equal(t, fmt.Sprintf("%s matA[matB[%d]]", name, j), matA[i], j)
+		}
+	}
+
+	return cnt
+}
+
+func TestAlgorithms(t *testing.T) {
+	test := func(a, b string, lcs int) {
+		la, lb := strings.Split(a, ""), strings.Split(b, "")
+		for name, algo := range Algorithms {
+			matA, matB := algo(la, lb)
+			cnt := checkMatching(t, fmt.Sprintf("%s(%q, %q)", name, a, b), la, lb, matA, matB)
+			if name == "dp" || name == "myers" {
+				assert.Equal(t, fmt.Sprintf("%s(%q, %q) matched", name, a, b), cnt, lcs)
+			}
+		}
+	}
+
+	test("abcd", "bcde", 3)
+	test("abcde", "", 0)
+	test("", "abcde", 0)
+	test("", "", 0)
+	test("abcde", "abcde", 5)
+	test("abcde", "dabce", 4)
+	test("abcde", "abfde", 4)
+	test("abcabba", "cbabac", 4)
+	test("xaxbxcx", "yaybycy", 3)
+}
+
+func TestAlgorithms_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() []string {
+		l := make([]string, rnd.Intn(30))
+		for i := range l {
+			l[i] = string(rune('a' + rnd.Intn(4)))
+		}
+		return l
+	}
+	for k := 0; k < 500; k++ {
+		a, b := gen(), gen()
+		dpA, dpB := DP(a, b)
+		lcs := checkMatching(t, "dp", a, b, dpA, dpB)
+		for name, algo := range Algorithms {
+			matA, matB := algo(a, b)
+			cnt := checkMatching(t, name, a, b, matA, matB)
+			if name == "myers" {
+				assert.Equal(t, fmt.Sprintf("myers(%v, %v) matched", a, b), cnt, lcs)
+			}
+		}
+	}
+}
+
+func TestMyers_Large(t *testing.T) {
+	// unrelated lines, except every 100th one
+	a, b := make([]string, 4000), make([]string, 4000)
+	for i := range a {
+		a[i], b[i] = fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+		if i%100 == 0 {
+			a[i], b[i] = "common", "common"
+		}
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	matA, matB := Myers(a, b)
+	runtime.ReadMemStats(&after)
+
+	assert.Equal(t, "matched", checkMatching(t, "myers", a, b, matA, matB), 40)
+	alloc := after.TotalAlloc - before.TotalAlloc
+	assert.True(t, fmt.Sprintf("allocated %d bytes", alloc), alloc < 1<<24)
+}
+
+var frobA = strings.Split(`#include <stdio.h>
+
+// Frobs foo heartily
+int frobnitz(int foo)
+{
+    int i;
+    for(i = 0; i < 10; i++)
+    {
+        printf("Your answer is: ");
+        printf("%d\n", foo);
+    }
+}
+
+int fact(int n)
+{
+    if(n > 1)
+    {
+        return fact(n-1) * n;
+    }
+    return 1;
+}
+
+int main(int argc, char **argv)
+{
+    frobnitz(fact(10));
+}`, "\n")
+
+var frobB = strings.Split(`#include <stdio.h>
+
+int fib(int n)
+{
+    if(n > 2)
+    {
+        return fib(n-1) + fib(n-2);
+    }
+    return 1;
+}
+
+// Frobs foo heartily
+int frobnitz(int foo)
+{
+    int i;
+    for(i = 0; i < 10; i++)
+    {
+        printf("%d\n", foo);
+    }
+}
+
+int main(int argc, char **argv)
+{
+    frobnitz(fib(10));
+}`, "\n")
+
+func TestReadable(t *testing.T) {
+	// frobnitz is kept as a whole, fib is inserted and fact is deleted.
+	expA := []int{0, 1, 11, 12, 13, 14, 15, 16, -1, 17, 18, 19, 20, -1, -1, -1, -1, -1, -1, -1, -1, -1, 21, 22, -1, 24}
+	expB := []int{0, 1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 2, 3, 4, 5, 6, 7, 9, 10, 11, 12, 22, 23, -1, 25}
+	for _, name := range []string{"patience", "histogram"} {
+		matA, matB := Algorithms[name](frobA, frobB)
+		checkMatching(t, name, frobA, frobB, matA, matB)
+		assert.StringEqual(t, name+" matA", matA, expA)
+		assert.StringEqual(t, name+" matB", matB, expB)
+	}
+}
@@ -0,0 +1,276 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NoNewlineMarker is the line written after a last line without a newline, as diff(1) does.
+const NoNewlineMarker = "\\ No newline at end of file"
+
+/*
+NoNewline marks the inputs whose last lines are not terminated by a newline.
+*/
+type NoNewline struct {
+	A, B bool
+}
+
+// SplitLines splits s into lines, and reports whether the last line is not terminated by a newline.
+func SplitLines(s string) (lines []string, noNewline bool) {
+	if s == "" {
+		return nil, false
+	}
+	noNewline = !strings.HasSuffix(s, "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n"), noNewline
+}
+
+// writeLine writes a line with a prefix, followed by NoNewlineMarker if it is the last line without a newline.
+func writeLine(w *bufio.Writer, prefix string, lines []string, i int, noNewline bool) {
+	fmt.Fprintf(w, "%s%s\n", prefix, lines[i])
+	if noNewline && i == len(lines)-1 {
+		fmt.Fprintln(w, NoNewlineMarker)
+	}
+}
+
+/*
+Hunk is a group of changes with surrounding unchanged lines. Lines a[A0:A1] and b[B0:B1] are in the hunk.
+*/
+type Hunk struct {
+	A0, A1, B0, B1 int
+}
+
+/*
+Hunks groups the changes in a matching into hunks, with at most context unchanged lines before and after each change. Changes separated by no more than 2*context unchanged lines are in the same hunk.
+*/
+func Hunks(matA, matB []int, context int) []Hunk {
+	if context < 0 {
+		context = 0
+	}
+
+	var hunks []Hunk
+	// i and j are the current positions, cur is the hunk being built
+	var cur *Hunk
+	for i, j := 0, 0; i < len(matA) || j < len(matB); {
+		if i < len(matA) && j < len(matB) && matA[i] == j {
+			i++
+			j++
+			continue
+		}
+
+		// a change region starting at (i, j)
+		ci, cj := i, j
+		for ; i < len(matA) && matA[i] < 0; i++ {
+		}
+		for ; j < len(matB) && matB[j] < 0; j++ {
+		}
+		if cur != nil && ci-cur.A1 <= 2*context {
+			cur.A1, cur.B1 = i, j
+			continue
+		}
+		if cur != nil {
+			hunks = append(hunks, *cur)
+		}
+		cur = &Hunk{A0: ci, A1: i, B0: cj, B1: j}
+		// extend backward by context lines
+		for k := 0; k < context && cur.A0 > 0 && cur.B0 > 0; k++ {
+			cur.A0--
+			cur.B0--
+		}
+	}
+	if cur != nil {
+		hunks = append(hunks, *cur)
+	}
+
+	// extend forward by context lines
+	for k := range hunks {
+		h := &hunks[k]
+		for c := 0; c < context && h.A1 < len(matA) && h.B1 < len(matB); c++ {
+			h.A1++
+			h.B1++
+		}
+	}
+
+	return hunks
+}
+
+// normalRange formats a range in the normal format: a single line number for at most one line, where an empty range is denoted by the line before it.
+func normalRange(lo, hi int) string {
+	switch hi - lo {
+	case 0:
+		return fmt.Sprint(lo)
+	case 1:
+		return fmt.Sprint(lo + 1)
+	}
+	return fmt.Sprintf("%d,%d", lo+1, hi)
+}
+
+// unifiedRange formats a range in the unified format.
+func unifiedRange(lo, hi int) string {
+	switch hi - lo {
+	case 0:
+		return fmt.Sprintf("%d,0", lo)
+	case 1:
+		return fmt.Sprint(lo + 1)
+	}
+	return fmt.Sprintf("%d,%d", lo+1, hi-lo)
+}
+
+// contextRange formats a range in the context format.
+func contextRange(lo, hi int) string {
+	switch hi - lo {
+	case 0:
+		return fmt.Sprint(lo)
+	case 1:
+		return fmt.Sprint(lo + 1)
+	}
+	return fmt.Sprintf("%d,%d", lo+1, hi)
+}
+
+/*
+WriteNormal writes the difference between lines a and b in the normal format, as diff does without format flags. Nothing is written if there are no changes.
+*/
+func WriteNormal(w io.Writer, a, b []string, matA, matB []int) error {
+	return WriteNormalNoNewline(w, a, b, matA, matB, NoNewline{})
+}
+
+/*
+WriteNormalNoNewline is similar to WriteNormal, but writes NoNewlineMarker after the last lines of inputs marked by nn.
+*/
+func WriteNormalNoNewline(w io.Writer, a, b []string, matA, matB []int, nn NoNewline) error {
+	bw := bufio.NewWriter(w)
+	for _, h := range Hunks(matA, matB, 0) {
+		op := "c"
+		switch {
+		case h.A0 == h.A1:
+			op = "a"
+		case h.B0 == h.B1:
+			op = "d"
+		}
+		fmt.Fprintf(bw, "%s%s%s\n", normalRange(h.A0, h.A1), op, normalRange(h.B0, h.B1))
+		for i := h.A0; i < h.A1; i++ {
+			writeLine(bw, "< ", a, i, nn.A)
+		}
+		if op == "c" {
+			bw.WriteString("---\n")
+		}
+		for j := h.B0; j < h.B1; j++ {
+			writeLine(bw, "> ", b, j, nn.B)
+		}
+	}
+
+	return bw.Flush()
+}
+
+/*
+WriteUnified writes the difference between lines a and b in the unified format, as diff -u does. headerA and headerB follow "--- " and "+++ " in the header, e.g. file names and modification times. Nothing is written if there are no changes.
+*/
+func WriteUnified(w io.Writer, headerA, headerB string, a, b []string, matA, matB []int, context int) error {
+	return WriteUnifiedNoNewline(w, headerA, headerB, a, b, matA, matB, context, NoNewline{})
+}
+
+/*
+WriteUnifiedNoNewline is similar to WriteUnified, but writes NoNewlineMarker after the last lines of inputs marked by nn.
+*/
+func WriteUnifiedNoNewline(w io.Writer, headerA, headerB string, a, b []string, matA, matB []int, context int, nn NoNewline) error {
+	hunks := Hunks(matA, matB, context)
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "--- %s\n+++ %s\n", headerA, headerB)
+	for _, h := range hunks {
+		fmt.Fprintf(bw, "@@ -%s +%s @@\n", unifiedRange(h.A0, h.A1), unifiedRange(h.B0, h.B1))
+		for i, j := h.A0, h.B0; i < h.A1 || j < h.B1; {
+			switch {
+			case i < h.A1 && matA[i] < 0:
+				writeLine(bw, "-", a, i, nn.A)
+				i++
+			case j < h.B1 && matB[j] < 0:
+				writeLine(bw, "+", b, j, nn.B)
+				j++
+			default:
+				writeLine(bw, " ", a, i, nn.A)
+				i++
+				j++
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+/*
+WriteContext writes the difference between lines a and b in the context format, as diff -c does. headerA and headerB follow "*** " and "--- " in the header. Nothing is written if there are no changes.
+*/
+func WriteContext(w io.Writer, headerA, headerB string, a, b []string, matA, matB []int, context int) error {
+	return WriteContextNoNewline(w, headerA, headerB, a, b, matA, matB, context, NoNewline{})
+}
+
+/*
+WriteContextNoNewline is similar to WriteContext, but writes NoNewlineMarker after the last lines of inputs marked by nn.
+*/
+func WriteContextNoNewline(w io.Writer, headerA, headerB string, a, b []string, matA, matB []int, context int, nn NoNewline) error {
+	hunks := Hunks(matA, matB, context)
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	// marks of lines: "  " for unchanged, "- " for deleted, "+ " for inserted and "! " for changed ones
+	marksA, marksB := make([]string, len(a)), make([]string, len(b))
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		if i < len(a) && j < len(b) && matA[i] == j {
+			marksA[i], marksB[j] = "  ", "  "
+			i++
+			j++
+			continue
+		}
+		ci, cj := i, j
+		for ; i < len(a) && matA[i] < 0; i++ {
+		}
+		for ; j < len(b) && matB[j] < 0; j++ {
+		}
+		markA, markB := "- ", "+ "
+		if i > ci && j > cj {
+			markA, markB = "! ", "! "
+		}
+		for k := ci; k < i; k++ {
+			marksA[k] = markA
+		}
+		for k := cj; k < j; k++ {
+			marksB[k] = markB
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "*** %s\n--- %s\n", headerA, headerB)
+	for _, h := range hunks {
+		bw.WriteString("***************\n")
+
+		fmt.Fprintf(bw, "*** %s ****\n", contextRange(h.A0, h.A1))
+		changed := false
+		for i := h.A0; i < h.A1; i++ {
+			changed = changed || matA[i] < 0
+		}
+		if changed {
+			for i := h.A0; i < h.A1; i++ {
+				writeLine(bw, marksA[i], a, i, nn.A)
+			}
+		}
+
+		fmt.Fprintf(bw, "--- %s ----\n", contextRange(h.B0, h.B1))
+		changed = false
+		for j := h.B0; j < h.B1; j++ {
+			changed = changed || matB[j] < 0
+		}
+		if changed {
+			for j := h.B0; j < h.B1; j++ {
+				writeLine(bw, marksB[j], b, j, nn.B)
+			}
+		}
+	}
+
+	return bw.Flush()
+}
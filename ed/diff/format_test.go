@@ -0,0 +1,154 @@
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestHunks(t *testing.T) {
+	test := func(a, b string, context int, exp []Hunk) {
+		matA, matB := Myers(strings.Split(a, ""), strings.Split(b, ""))
+		assert.StringEqual(t, "Hunks("+a+", "+b+")", Hunks(matA, matB, context), exp)
+	}
+
+	test("abc", "abc", 3, nil)
+	test("", "ab", 3, []Hunk{{0, 0, 0, 2}})
+	test("abcdefgh", "abXdefgh", 1, []Hunk{{1, 4, 1, 4}})
+	test("abcdefgh", "Xbcdefgh", 1, []Hunk{{0, 2, 0, 2}})
+	// gaps of 2*context lines are merged
+	test("abcdefgh", "aXcdXfgh", 1, []Hunk{{0, 6, 0, 6}})
+	test("abcdefgh", "aXcdeXgh", 1, []Hunk{{0, 3, 0, 3}, {4, 7, 4, 7}})
+}
+
+var (
+	fmtA = strings.Split("1 2 3 4 5 6 7 8 9 10 11 12", " ")
+	fmtB = strings.Split("1 2 x 4 5 6 7 8 10 11 12 13", " ")
+)
+
+func TestWriteNormal(t *testing.T) {
+	matA, matB := Myers(fmtA, fmtB)
+	var out bytes.Buffer
+	assert.NoError(t, WriteNormal(&out, fmtA, fmtB, matA, matB))
+	// same as GNU diff
+	assert.Equal(t, "output", out.String(), `3c3
+< 3
+---
+> x
+9d8
+< 9
+12a12
+> 13
+`)
+
+	out.Reset()
+	matA, matB = Myers(fmtA, fmtA)
+	assert.NoError(t, WriteNormal(&out, fmtA, fmtA, matA, matB))
+	assert.Equal(t, "output", out.String(), "")
+}
+
+func TestWriteUnified(t *testing.T) {
+	matA, matB := Myers(fmtA, fmtB)
+	var out bytes.Buffer
+	assert.NoError(t, WriteUnified(&out, "a", "b", fmtA, fmtB, matA, matB, 1))
+	// same as GNU diff -U 1
+	assert.Equal(t, "output", out.String(), `--- a
++++ b
+@@ -2,3 +2,3 @@
+ 2
+-3
++x
+ 4
+@@ -8,3 +8,2 @@
+ 8
+-9
+ 10
+@@ -12 +11,2 @@
+ 12
++13
+`)
+
+	out.Reset()
+	matA, matB = Myers(fmtA, fmtA)
+	assert.NoError(t, WriteUnified(&out, "a", "b", fmtA, fmtA, matA, matB, 1))
+	assert.Equal(t, "output", out.String(), "")
+}
+
+func TestWriteContext(t *testing.T) {
+	matA, matB := Myers(fmtA, fmtB)
+	var out bytes.Buffer
+	assert.NoError(t, WriteContext(&out, "a", "b", fmtA, fmtB, matA, matB, 1))
+	// same as GNU diff -C 1
+	assert.Equal(t, "output", out.String(), `*** a
+--- b
+***************
+*** 2,4 ****
+  2
+! 3
+  4
+--- 2,4 ----
+  2
+! x
+  4
+***************
+*** 8,10 ****
+  8
+- 9
+  10
+--- 8,9 ----
+***************
+*** 12 ****
+--- 11,12 ----
+  12
++ 13
+`)
+}
+
+func TestSplitLines(t *testing.T) {
+	lines, nn := SplitLines("a\nb\n")
+	assert.StringEqual(t, "lines", lines, []string{"a", "b"})
+	assert.False(t, "nn", nn)
+	lines, nn = SplitLines("a\nb")
+	assert.StringEqual(t, "lines", lines, []string{"a", "b"})
+	assert.True(t, "nn", nn)
+	lines, nn = SplitLines("")
+	assert.Equal(t, "lines", len(lines), 0)
+	assert.False(t, "nn", nn)
+}
+
+func TestWriteNoNewline(t *testing.T) {
+	a, b := []string{"a", "b"}, []string{"a", "c"}
+	matA, matB := Myers(a, b)
+	var out bytes.Buffer
+	assert.NoError(t, WriteUnifiedNoNewline(&out, "x1", "x3", a, b, matA, matB, 1, NoNewline{A: true, B: true}))
+	// same as GNU diff -U 1
+	assert.Equal(t, "unified", out.String(), `--- x1
++++ x3
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++c
+\ No newline at end of file
+`)
+
+	out.Reset()
+	assert.NoError(t, WriteNormalNoNewline(&out, a, b, matA, matB, NoNewline{B: true}))
+	assert.Equal(t, "normal", out.String(), "2c2\n< b\n---\n> c\n\\ No newline at end of file\n")
+
+	out.Reset()
+	assert.NoError(t, WriteContextNoNewline(&out, "x1", "x3", a, b, matA, matB, 1, NoNewline{A: true}))
+	assert.Equal(t, "context", out.String(), `*** x1
+--- x3
+***************
+*** 1,2 ****
+  a
+! b
+\ No newline at end of file
+--- 1,2 ----
+  a
+! c
+`)
+}
@@ -1,20 +1,67 @@
 /*
 ed package provides some functions for generalized edit-distance calculation.
 
-String calculates the standard edit-distance, and StringFull returns an extra longest-common-string.
+String calculates the standard edit-distance, and StringFull returns an extra longest-common-string. Damerau also allows transpositions, and Jaro and JaroWinkler calculate similarities.
 
 EditDistance calculates the generalized edit-distance, and EditDistanceFull returns extra matching infomation. Base is a helper type using as a base type for Interface implementations.
 
 EditDistanceF and EditDistanceFFull are similar to EditDistance and EditDistanceFull, but using parameter and functions instead of an interface. This is sometimes more easy to use. ConstCost is a helper function.
+
+StringCtx, EditDistanceCtx and EditDistanceFullCtx can be canceled by a context, and the size of the DP table can be limited with WithMaxCells.
+
+EditDistanceParallel and EditDistanceFullParallel process anti-diagonals of tiles of the DP table concurrently for very long lists, the latter with the same matching as EditDistanceFull in O(m + n log m) space.
+
+If an Interface implements Equaler, EditDistance, EditDistanceFull and their Parallel variants skip the common prefix and suffix of the two lists when that keeps the distance unchanged.
+
+Incremental and StringIncremental update the edit-distance in O(m) time as elements are appended to or removed from the end of the second list, e.g. for live typing feedback.
+
+Costs must be non-negative. Infinite is the cost of a forbidden operation, and sums of costs saturate at it. EditDistance, EditDistanceFull, EditDistanceF and EditDistanceFFull do not check costs. Their Checked variants and the Ctx variants report negative costs with a *NegativeCostError, and return ErrNoAlignment if no finite alignment exists.
 */
 package ed
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"unicode/utf8"
-
-	//    "fmt"
 )
 
+/*
+Infinite is the cost of a forbidden operation. Sums of costs saturate at Infinite in EditDistance, EditDistanceFull, EditDistanceF, EditDistanceFFull and the Ctx variants, so a distance of Infinite means no finite alignment exists.
+*/
+const Infinite = math.MaxInt
+
+// ErrNoAlignment is returned by the Ctx and Checked functions if no alignment with a finite cost exists.
+var ErrNoAlignment = errors.New("ed: no alignment with a finite cost")
+
+// NegativeCostError is returned by the Ctx and Checked functions if a negative cost is found.
+type NegativeCostError struct {
+	// Op is "change", "delete" or "insert"
+	Op string
+	// Indexes of the elements, -1 if not applicable
+	IA, IB int
+	Cost   int
+}
+
+func (e *NegativeCostError) Error() string {
+	switch e.Op {
+	case "delete":
+		return fmt.Sprintf("ed: negative cost %d of deleting A[%d]", e.Cost, e.IA)
+	case "insert":
+		return fmt.Sprintf("ed: negative cost %d of inserting B[%d]", e.Cost, e.IB)
+	}
+	return fmt.Sprintf("ed: negative cost %d of changing A[%d] to B[%d]", e.Cost, e.IA, e.IB)
+}
+
+// addCost returns a + b, saturating at Infinite.
+func addCost(a, b int) int {
+	if a >= Infinite || b >= Infinite || b > 0 && a > Infinite-b {
+		return Infinite
+	}
+
+	return a + b
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -26,9 +73,27 @@ func min(a, b int) int {
 /*
 String calculates the edit-distance between two strings. Input strings must be UTF-8 encoded.
 
-The time complexity is O(mn) where m and n are lengths of a and b, and space complexity is O(n).
+The time complexity is O(mn) where m and n are lengths of a and b without their common prefix and suffix, and space complexity is O(n).
 */
 func String(a, b string) int {
+	// the common prefix and suffix do not change the distance
+	for len(a) > 0 && len(b) > 0 {
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			break
+		}
+		a, b = a[sa:], b[sb:]
+	}
+	for len(a) > 0 && len(b) > 0 {
+		ra, sa := utf8.DecodeLastRuneInString(a)
+		rb, sb := utf8.DecodeLastRuneInString(b)
+		if ra != rb {
+			break
+		}
+		a, b = a[:len(a)-sa], b[:len(b)-sb]
+	}
+
 	f := make([]int, utf8.RuneCountInString(b)+1)
 
 	for j := range f {
@@ -212,25 +277,30 @@ type Interface interface {
 }
 
 /*
-EditDistance returns the edit-distance defined by Interface.
+EditDistance returns the edit-distance defined by Interface. If in implements Equaler, the common prefix and suffix may be skipped.
+
+Costs are not checked: the result is undefined if a cost is negative, and the distance is Infinite if no finite alignment exists. Use EditDistanceChecked to check them.
 
 The time complexity is O(mn) where m and n are lengths of a and b, and space complexity is O(n).
 */
 func EditDistance(in Interface) int {
+	if mid, _, _ := trim(in); mid != nil {
+		in = mid
+	}
 	la, lb := in.LenA(), in.LenB()
 
 	f := make([]int, lb+1)
 
 	for j := 1; j <= lb; j++ {
-		f[j] = f[j-1] + in.CostOfIns(j-1)
+		f[j] = addCost(f[j-1], in.CostOfIns(j-1))
 	}
 
 	for i := 0; i < la; i++ {
 		fj1 := f[0] // fj1 is the value of f[j - 1] in last iteration
-		f[0] += in.CostOfDel(i)
+		f[0] = addCost(f[0], in.CostOfDel(i))
 		for j := 1; j <= lb; j++ {
-			mn := min(f[j]+in.CostOfDel(i), f[j-1]+in.CostOfIns(j-1)) // delete & insert
-			mn = min(mn, fj1+in.CostOfChange(i, j-1))                 // change/matched
+			mn := min(addCost(f[j], in.CostOfDel(i)), addCost(f[j-1], in.CostOfIns(j-1))) // delete & insert
+			mn = min(mn, addCost(fj1, in.CostOfChange(i, j-1)))                           // change/matched
 
 			fj1, f[j] = f[j], mn // save f[j] to fj1(j is about to increase), update f[j] to mn
 		}
@@ -271,37 +341,44 @@ func matchingFromOps(la, lb int, ops []byte) (matA, matB []int) {
 
 /*
 EditDistanceFull returns the edit-distance and corresponding match indexes defined by Interface.
-Each element in matA and matB is the index in the other list, if it is equal to or greater than zero; or -1 meaning a deleting or inserting in matA or matB, respectively.
+Each element in matA and matB is the index in the other list, if it is equal to or greater than zero; or -1 meaning a deleting or inserting in matA or matB, respectively. If in implements Equaler, the common prefix and suffix may be skipped in the DP and matched.
+
+Costs are not checked: the result is undefined if a cost is negative, and the distance is Infinite if no finite alignment exists. Use EditDistanceFullChecked to check them.
 
 The time and space complexity are all O(mn) where m and n are lengths of a and b.
 
 NOTE if detailed matching information is not necessary, call EditDistance instead because it needs much less memories.
 */
 func EditDistanceFull(in Interface) (dist int, matA, matB []int) {
+	if mid, pre, suf := trim(in); mid != nil {
+		dist, midA, midB := EditDistanceFull(mid)
+		matA, matB = splice(in.LenA(), in.LenB(), pre, suf, midA, midB)
+		return dist, matA, matB
+	}
 	la, lb := in.LenA(), in.LenB()
 
 	f := make([]int, lb+1)
 	ops := make([]byte, la*lb)
 
 	for j := 1; j <= lb; j++ {
-		f[j] = f[j-1] + in.CostOfIns(j-1)
+		f[j] = addCost(f[j-1], in.CostOfIns(j-1))
 	}
 
 	// Matching with dynamic programming
 	p := 0
 	for i := 0; i < la; i++ {
 		fj1 := f[0] // fj1 is the value of f[j - 1] in last iteration
-		f[0] += in.CostOfDel(i)
+		f[0] = addCost(f[0], in.CostOfDel(i))
 		for j := 1; j <= lb; j++ {
-			mn, op := f[j]+in.CostOfDel(i), opDEL // delete
+			mn, op := addCost(f[j], in.CostOfDel(i)), opDEL // delete
 
-			if v := f[j-1] + in.CostOfIns(j-1); v < mn {
+			if v := addCost(f[j-1], in.CostOfIns(j-1)); v < mn {
 				// insert
 				mn, op = v, opINS
 			}
 
 			// change/matched
-			if v := fj1 + in.CostOfChange(i, j-1); v < mn {
+			if v := addCost(fj1, in.CostOfChange(i, j-1)); v < mn {
 				// insert
 				mn, op = v, opCHANGE
 			}
@@ -317,7 +394,9 @@ func EditDistanceFull(in Interface) (dist int, matA, matB []int) {
 }
 
 /*
-EditDistanceFunc returns the edit-distance defined by parameters and functions.
+EditDistanceF returns the edit-distance defined by parameters and functions.
+
+Costs are not checked: the result is undefined if a cost is negative, and the distance is Infinite if no finite alignment exists. Use EditDistanceFChecked to check them.
 
 The time complexity is O(mn) where m and n are lengths of a and b, and space complexity is O(n).
 */
@@ -327,15 +406,15 @@ func EditDistanceF(lenA, lenB int, costOfChange func(iA, iB int) int, costOfDel
 	f := make([]int, lb+1)
 
 	for j := 1; j <= lb; j++ {
-		f[j] = f[j-1] + costOfIns(j-1)
+		f[j] = addCost(f[j-1], costOfIns(j-1))
 	}
 
 	for i := 0; i < la; i++ {
 		fj1 := f[0] // fj1 is the value of f[j - 1] in last iteration
-		f[0] += costOfDel(i)
+		f[0] = addCost(f[0], costOfDel(i))
 		for j := 1; j <= lb; j++ {
-			mn := min(f[j]+costOfDel(i), f[j-1]+costOfIns(j-1)) // delete & insert
-			mn = min(mn, fj1+costOfChange(i, j-1))              // change/matched
+			mn := min(addCost(f[j], costOfDel(i)), addCost(f[j-1], costOfIns(j-1))) // delete & insert
+			mn = min(mn, addCost(fj1, costOfChange(i, j-1)))                        // change/matched
 
 			fj1, f[j] = f[j], mn // save f[j] to fj1(j is about to increase), update f[j] to mn
 		}
@@ -348,6 +427,8 @@ func EditDistanceF(lenA, lenB int, costOfChange func(iA, iB int) int, costOfDel
 EditDistanceFFull returns the edit-distance and corresponding match indexes defined by parameters and functions.
 Each element in matA and matB is the index in the other list, if it is equal to or greater than zero; or -1 meaning a deleting or inserting in matA or matB, respectively.
 
+Costs are not checked: the result is undefined if a cost is negative, and the distance is Infinite if no finite alignment exists. Use EditDistanceFFullChecked to check them.
+
 The time and space complexity are all O(mn) where m and n are lengths of a and b.
 
 NOTE if detailed matching information is not necessary, call EditDistance instead because it needs much less memories.
@@ -359,24 +440,24 @@ func EditDistanceFFull(lenA, lenB int, costOfChange func(iA, iB int) int, costOf
 	ops := make([]byte, la*lb)
 
 	for j := 1; j <= lb; j++ {
-		f[j] = f[j-1] + costOfIns(j-1)
+		f[j] = addCost(f[j-1], costOfIns(j-1))
 	}
 
 	// Matching with dynamic programming
 	p := 0
 	for i := 0; i < la; i++ {
 		fj1 := f[0] // fj1 is the value of f[j - 1] in last iteration
-		f[0] += costOfDel(i)
+		f[0] = addCost(f[0], costOfDel(i))
 		for j := 1; j <= lb; j++ {
-			mn, op := f[j]+costOfDel(i), opDEL // delete
+			mn, op := addCost(f[j], costOfDel(i)), opDEL // delete
 
-			if v := f[j-1] + costOfIns(j-1); v < mn {
+			if v := addCost(f[j-1], costOfIns(j-1)); v < mn {
 				// insert
 				mn, op = v, opINS
 			}
 
 			// change/matched
-			if v := fj1 + costOfChange(i, j-1); v < mn {
+			if v := addCost(fj1, costOfChange(i, j-1)); v < mn {
 				// insert
 				mn, op = v, opCHANGE
 			}
@@ -183,6 +183,48 @@ func TestEditDistanceFFull(t *testing.T) {
 	test("abcde", "abfde", 100, []int{0, 1, 2, 3, 4}, []int{0, 1, 2, 3, 4})
 }
 
+type funcInterface struct {
+	la, lb       int
+	costOfChange func(iA, iB int) int
+	costOfDel    func(iA int) int
+	costOfIns    func(iB int) int
+}
+
+func (in *funcInterface) LenA() int                   { return in.la }
+func (in *funcInterface) LenB() int                   { return in.lb }
+func (in *funcInterface) CostOfChange(iA, iB int) int { return in.costOfChange(iA, iB) }
+func (in *funcInterface) CostOfDel(iA int) int        { return in.costOfDel(iA) }
+func (in *funcInterface) CostOfIns(iB int) int        { return in.costOfIns(iB) }
+
+func TestInfinite(t *testing.T) {
+	test := func(a, b string, costOfIndel int, d int, matA, matB []int) {
+		costOfChange := func(iA, iB int) int {
+			// only matching is allowed
+			return Ternary(a[iA] == b[iB], 0, Infinite)
+		}
+		in := &funcInterface{len(a), len(b), costOfChange, ConstCost(costOfIndel), ConstCost(costOfIndel)}
+
+		assert.Equal(t, fmt.Sprintf("EditDistance between %s and %s", a, b), EditDistance(in), d)
+		assert.Equal(t, fmt.Sprintf("EditDistanceF between %s and %s", a, b), EditDistanceF(len(a), len(b), costOfChange, ConstCost(costOfIndel), ConstCost(costOfIndel)), d)
+
+		actD, actMatA, actMatB := EditDistanceFull(in)
+		assert.Equal(t, fmt.Sprintf("EditDistanceFull between %s and %s", a, b), actD, d)
+		if matA != nil {
+			assert.StringEqual(t, fmt.Sprintf("matA for matchting between %s and %s", a, b), actMatA, matA)
+			assert.StringEqual(t, fmt.Sprintf("matB for matchting between %s and %s", a, b), actMatB, matB)
+		}
+		actD, _, _ = EditDistanceFFull(len(a), len(b), costOfChange, ConstCost(costOfIndel), ConstCost(costOfIndel))
+		assert.Equal(t, fmt.Sprintf("EditDistanceFFull between %s and %s", a, b), actD, d)
+	}
+
+	test("abcd", "bcde", 1, 2, []int{-1, 0, 1, 2}, []int{1, 2, 3, -1})
+	test("ab", "cd", 1, 4, []int{-1, -1}, []int{-1, -1})
+	test("ab", "ab", Infinite, 0, []int{0, 1}, []int{0, 1})
+	test("ab", "ac", Infinite, Infinite, nil, nil)
+	test("ab", "ac", Infinite-1, Infinite, nil, nil)
+	test("", "", Infinite, 0, []int{}, []int{})
+}
+
 func ExampleEditDistanceF() {
 	a, b := "abcd", "bcde"
 	d := EditDistanceF(len(a), len(b), func(iA, iB int) int {
@@ -0,0 +1,342 @@
+/*
+godiff package compares two versions of a Go source file at the AST level.
+
+Files are parsed with go/parser without comments, and declarations are compared by their syntax trees ignoring positions, so differences only in formatting or comments are ignored. Top-level declarations are aligned by their names using the ed engine, declarations out of the alignment with the same names are regarded as moved, and statements of modified functions are aligned by their canonical forms.
+*/
+package godiff
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"reflect"
+	"strconv"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Kind is the kind of a change.
+type Kind int
+
+// Kinds of changes
+const (
+	Added Kind = iota
+	Removed
+	Modified
+	// Moved is a declaration moved to another place without modification. Moved and modified declarations are reported as Modified.
+	Moved
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	case Moved:
+		return "moved"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// StmtChange is a change of a top-level statement in a function body.
+type StmtChange struct {
+	Kind Kind
+	// Positions of the statement in the source and destination file. The position is invalid if the statement does not exist in that file.
+	PosA, PosB token.Position
+}
+
+// Change is a change of a top-level declaration.
+type Change struct {
+	Kind Kind
+	// Name of the declaration, e.g. "func F", "func (*T) M", "type T", "var x, y", "const C", "import \"fmt\"" or "package".
+	Name string
+	// Positions of the declaration in the source and destination file. The position is invalid if the declaration does not exist in that file.
+	PosA, PosB token.Position
+	// Changes of statements, only for modified functions
+	Stmts []StmtChange
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%v %s at %v", c.Kind, c.Name, c.PosB)
+	case Removed:
+		return fmt.Sprintf("%v %s at %v", c.Kind, c.Name, c.PosA)
+	}
+	return fmt.Sprintf("%v %s at %v -> %v", c.Kind, c.Name, c.PosA, c.PosB)
+}
+
+// Result is the result of comparing two Go files.
+type Result struct {
+	// Changes in the order of the source file, with added declarations in place.
+	Changes []Change
+}
+
+// FormattingOnly returns true if the two files differ only in formatting or comments. Moved declarations are regarded as changes.
+func (r *Result) FormattingOnly() bool {
+	return len(r.Changes) == 0
+}
+
+// decl is a top-level declaration, or a spec of a GenDecl.
+type decl struct {
+	name string
+	node ast.Node
+	text string
+}
+
+type file struct {
+	fset  *token.FileSet
+	pkg   string
+	decls []decl
+}
+
+var (
+	posType   = reflect.TypeOf(token.NoPos)
+	objType   = reflect.TypeOf((*ast.Object)(nil))
+	scopeType = reflect.TypeOf((*ast.Scope)(nil))
+)
+
+func writeCanonical(buf *bytes.Buffer, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+		if v.Type() == objType || v.Type() == scopeType {
+			// resolved objects are derived information and may contain cycles
+			return
+		}
+		writeCanonical(buf, v.Elem())
+
+	case reflect.Struct:
+		buf.WriteString(v.Type().Name())
+		buf.WriteByte('{')
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).Type() == posType {
+				continue
+			}
+			writeCanonical(buf, v.Field(i))
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('}')
+
+	case reflect.Slice:
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			writeCanonical(buf, v.Index(i))
+			buf.WriteByte(',')
+		}
+		buf.WriteByte(']')
+
+	case reflect.String:
+		buf.WriteString(strconv.Quote(v.String()))
+
+	default:
+		fmt.Fprint(buf, v)
+	}
+}
+
+// canonical returns a string of the AST node without positions. Comments are not parsed so they are not included either.
+func canonical(node interface{}) string {
+	var buf bytes.Buffer
+	writeCanonical(&buf, reflect.ValueOf(node))
+
+	return buf.String()
+}
+
+// typeString returns the source of a type expression, e.g. "*T".
+func typeString(x ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, token.NewFileSet(), x)
+
+	return buf.String()
+}
+
+func parse(filename string, src []byte) (*file, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fl := &file{fset: fset, pkg: f.Name.Name}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			name := "func " + d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				name = fmt.Sprintf("func (%s) %s", typeString(d.Recv.List[0].Type), d.Name.Name)
+			}
+			fl.decls = append(fl.decls, decl{name: name, node: d, text: canonical(d)})
+
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				var name string
+				switch s := s.(type) {
+				case *ast.ImportSpec:
+					name = "import " + s.Path.Value
+				case *ast.TypeSpec:
+					name = "type " + s.Name.Name
+				case *ast.ValueSpec:
+					name = d.Tok.String() + " "
+					for i, n := range s.Names {
+						if i > 0 {
+							name += ", "
+						}
+						name += n.Name
+					}
+				}
+				fl.decls = append(fl.decls, decl{name: name, node: s, text: d.Tok.String() + " " + canonical(s)})
+			}
+
+		default:
+			fl.decls = append(fl.decls, decl{name: "bad declaration", node: d, text: canonical(d)})
+		}
+	}
+
+	return fl, nil
+}
+
+// align matches two lists of n and m elements with the ed engine. Elements with different keys are never matched.
+func align(n, m int, sameKey func(iA, iB int) bool, costOfChange func(iA, iB int) int) (matA, matB []int) {
+	_, matA, matB = ed.EditDistanceFFull(n, m, func(iA, iB int) int {
+		if !sameKey(iA, iB) {
+			// more than a deleting plus an inserting
+			return 3
+		}
+		return costOfChange(iA, iB)
+	}, ed.ConstCost(1), ed.ConstCost(1))
+
+	return matA, matB
+}
+
+// walk calls the functions for each deleted, inserted or matched pair in order.
+func walk(matA, matB []int, del func(iA int), ins func(iB int), match func(iA, iB int)) {
+	for i, j := 0, 0; i < len(matA) || j < len(matB); {
+		switch {
+		case i < len(matA) && matA[i] < 0:
+			del(i)
+			i++
+		case j < len(matB) && matB[j] < 0:
+			ins(j)
+			j++
+		default:
+			match(i, j)
+			i++
+			j++
+		}
+	}
+}
+
+func diffStmts(fa, fb *file, a, b *ast.FuncDecl) []StmtChange {
+	var la, lb []ast.Stmt
+	if a.Body != nil {
+		la = a.Body.List
+	}
+	if b.Body != nil {
+		lb = b.Body.List
+	}
+	ta, tb := make([]string, len(la)), make([]string, len(lb))
+	for i, s := range la {
+		ta[i] = canonical(s)
+	}
+	for j, s := range lb {
+		tb[j] = canonical(s)
+	}
+
+	matA, matB := align(len(la), len(lb), func(iA, iB int) bool {
+		return true
+	}, func(iA, iB int) int {
+		// a modifying is preferred to a deleting plus an inserting
+		return ed.Ternary(ta[iA] == tb[iB], 0, 1)
+	})
+
+	var changes []StmtChange
+	walk(matA, matB, func(iA int) {
+		changes = append(changes, StmtChange{Kind: Removed, PosA: fa.fset.Position(la[iA].Pos())})
+	}, func(iB int) {
+		changes = append(changes, StmtChange{Kind: Added, PosB: fb.fset.Position(lb[iB].Pos())})
+	}, func(iA, iB int) {
+		if ta[iA] != tb[iB] {
+			changes = append(changes, StmtChange{Kind: Modified, PosA: fa.fset.Position(la[iA].Pos()), PosB: fb.fset.Position(lb[iB].Pos())})
+		}
+	})
+
+	return changes
+}
+
+/*
+Diff parses two versions of a Go file and returns the changes of declarations. The filenames are used for positions only; src must be non-nil.
+*/
+func Diff(filenameA string, srcA []byte, filenameB string, srcB []byte) (*Result, error) {
+	fa, err := parse(filenameA, srcA)
+	if err != nil {
+		return nil, err
+	}
+	fb, err := parse(filenameB, srcB)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{}
+	if fa.pkg != fb.pkg {
+		res.Changes = append(res.Changes, Change{Kind: Modified, Name: "package"})
+	}
+
+	da, db := fa.decls, fb.decls
+	matA, matB := align(len(da), len(db), func(iA, iB int) bool {
+		return da[iA].name == db[iB].name
+	}, func(iA, iB int) int {
+		return 0
+	})
+
+	// Declarations out of the alignment are paired by names as moved ones.
+	insByName := make(map[string][]int)
+	for j, i := range matB {
+		if i < 0 {
+			insByName[db[j].name] = append(insByName[db[j].name], j)
+		}
+	}
+	movedA, movedB := make(map[int]int), make(map[int]bool)
+	for i, j := range matA {
+		if js := insByName[da[i].name]; j < 0 && len(js) > 0 {
+			movedA[i], movedB[js[0]] = js[0], true
+			insByName[da[i].name] = js[1:]
+		}
+	}
+
+	compare := func(iA, iB int, moved bool) {
+		if da[iA].text == db[iB].text && !moved {
+			return
+		}
+		c := Change{Kind: Modified, Name: da[iA].name, PosA: fa.fset.Position(da[iA].node.Pos()), PosB: fb.fset.Position(db[iB].node.Pos())}
+		if da[iA].text == db[iB].text {
+			c.Kind = Moved
+		} else if a, ok := da[iA].node.(*ast.FuncDecl); ok {
+			c.Stmts = diffStmts(fa, fb, a, db[iB].node.(*ast.FuncDecl))
+		}
+		res.Changes = append(res.Changes, c)
+	}
+	walk(matA, matB, func(iA int) {
+		if iB, ok := movedA[iA]; ok {
+			compare(iA, iB, true)
+			return
+		}
+		res.Changes = append(res.Changes, Change{Kind: Removed, Name: da[iA].name, PosA: fa.fset.Position(da[iA].node.Pos())})
+	}, func(iB int) {
+		if !movedB[iB] {
+			res.Changes = append(res.Changes, Change{Kind: Added, Name: db[iB].name, PosB: fb.fset.Position(db[iB].node.Pos())})
+		}
+	}, func(iA, iB int) {
+		compare(iA, iB, false)
+	})
+
+	return res, nil
+}
@@ -0,0 +1,126 @@
+package godiff
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+const srcA = `package p
+
+import "fmt"
+
+// T is a type.
+type T struct{ X int }
+
+func (t *T) Print() {
+	fmt.Println(t.X)
+}
+
+func F(a int) int {
+	b := a + 1
+	return b * 2
+}
+
+func G() {}
+`
+
+func TestDiff_FormattingOnly(t *testing.T) {
+	srcB := `package p
+
+import (
+	"fmt"
+)
+
+// T is a type with a new comment.
+type T struct {
+	X int // the value
+}
+
+func (t *T) Print() { fmt.Println(t.X) }
+
+func F(a int) int {
+	b := a + 1
+
+	return b * 2
+}
+
+func G() {
+}
+`
+	res, err := Diff("a.go", []byte(srcA), "b.go", []byte(srcB))
+	assert.NoErrorOrDie(t, err)
+	assert.True(t, "FormattingOnly", res.FormattingOnly())
+	assert.Equal(t, "len(Changes)", len(res.Changes), 0)
+}
+
+func TestDiff(t *testing.T) {
+	srcB := `package p
+
+import "fmt"
+
+type T struct{ X, Y int }
+
+func F(a int) int {
+	b := a + 2
+	fmt.Println(b)
+	return b * 2
+}
+
+func (t *T) Print() {
+	fmt.Println(t.X)
+}
+
+var v = 1
+`
+	res, err := Diff("a.go", []byte(srcA), "b.go", []byte(srcB))
+	assert.NoErrorOrDie(t, err)
+	assert.False(t, "FormattingOnly", res.FormattingOnly())
+
+	var act []string
+	for _, c := range res.Changes {
+		act = append(act, c.String())
+		for _, s := range c.Stmts {
+			act = append(act, fmt.Sprintf("  %v %v %v", s.Kind, s.PosA, s.PosB))
+		}
+	}
+	assert.StringEqual(t, "Changes", act, []string{
+		"modified type T at a.go:6:6 -> b.go:5:6",
+		"modified func F at a.go:12:1 -> b.go:7:1",
+		"  modified a.go:13:2 b.go:8:2",
+		"  added - b.go:9:2",
+		"removed func G at a.go:17:1",
+		"added var v at b.go:17:5",
+	})
+}
+
+func TestDiff_Moved(t *testing.T) {
+	srcB := `package p
+
+import "fmt"
+
+func G() {}
+
+type T struct{ X int }
+
+func (t *T) Print() {
+	fmt.Println(t.X)
+}
+
+func F(a int) int {
+	b := a + 1
+	return b * 2
+}
+`
+	res, err := Diff("a.go", []byte(srcA), "b.go", []byte(srcB))
+	assert.NoErrorOrDie(t, err)
+	assert.StringEqual(t, "Changes", res.Changes, []string{
+		"moved func G at a.go:17:1 -> b.go:5:1",
+	})
+}
+
+func TestDiff_Error(t *testing.T) {
+	_, err := Diff("a.go", []byte("package p\nfunc {"), "b.go", []byte(srcA))
+	assert.Error(t, err)
+}
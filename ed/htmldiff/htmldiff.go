@@ -0,0 +1,317 @@
+/*
+htmldiff package renders a line diff as a self-contained HTML page.
+
+Lines are aligned by an algorithm of the ed/diff package. Changed lines are paired and their differences are highlighted at rune level with diff.Myers, unless the product of their lengths exceeds 1<<22, in which case the whole lines are highlighted. The page uses inline CSS and no scripts or external assets; long unchanged regions are collapsed with <details> elements.
+*/
+package htmldiff
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/daviddengcn/go-algs/ed/diff"
+)
+
+// Mode is the layout of the report.
+type Mode int
+
+// Layouts of the report
+const (
+	// SideBySide shows the two files in two columns.
+	SideBySide Mode = iota
+	// Inline shows deleted lines followed by inserted lines in one column.
+	Inline
+)
+
+// Options controls the report.
+type Options struct {
+	Mode Mode
+	// Title of the page. If empty, "NameA vs NameB" is used.
+	Title string
+	// Names of the two files shown in the header
+	NameA, NameB string
+	// Algorithm aligning lines. If nil, diff.Histogram is used.
+	Algorithm diff.Algorithm
+	// Context is the number of unchanged lines shown around changes, longer unchanged regions are collapsed. If zero, 3 is used; if negative, nothing is collapsed.
+	Context int
+}
+
+const css = `body{font-family:sans-serif;margin:1em}
+h1{font-size:1.2em}
+.diff{font-family:monospace;font-size:13px;border:1px solid #ccc}
+.row{display:grid;white-space:pre-wrap;word-break:break-all}
+.side .row{grid-template-columns:4em 1fr 4em 1fr}
+.inline .row{grid-template-columns:4em 4em 1.5em 1fr}
+.ln{color:#999;text-align:right;padding-right:.5em;user-select:none}
+.mk{user-select:none}
+.d{background:#ffebe9}
+.i{background:#e6ffec}
+.d .hl{background:#ffc1c0}
+.i .hl{background:#abf2bc}
+.e{background:#fff}
+details{background:#f6f8fa;border-top:1px solid #eee;border-bottom:1px solid #eee}
+summary{color:#666;cursor:pointer;padding:2px 1em}
+`
+
+// Kinds of rows
+const (
+	rowEqual = iota
+	rowDel
+	rowIns
+	rowChange
+)
+
+type row struct {
+	kind   int
+	iA, iB int
+}
+
+// rows converts a matching into rows. In each region of changes, deleted and inserted lines are paired in order as changed lines.
+func rows(matA, matB []int) []row {
+	var res []row
+	for i, j := 0, 0; i < len(matA) || j < len(matB); {
+		if i < len(matA) && j < len(matB) && matA[i] == j {
+			res = append(res, row{rowEqual, i, j})
+			i++
+			j++
+			continue
+		}
+
+		var dels, inss []int
+		for ; i < len(matA) && matA[i] < 0; i++ {
+			dels = append(dels, i)
+		}
+		for ; j < len(matB) && matB[j] < 0; j++ {
+			inss = append(inss, j)
+		}
+		for k := 0; k < len(dels) || k < len(inss); k++ {
+			switch {
+			case k >= len(inss):
+				res = append(res, row{rowDel, dels[k], -1})
+			case k >= len(dels):
+				res = append(res, row{rowIns, -1, inss[k]})
+			default:
+				res = append(res, row{rowChange, dels[k], inss[k]})
+			}
+		}
+	}
+
+	return res
+}
+
+// maxHighlightCells is the maximum product of lengths of two lines highlighted at rune level.
+var maxHighlightCells = 1 << 22
+
+// runeStrings returns the runes of rs as strings.
+func runeStrings(rs []rune) []string {
+	strs := make([]string, len(rs))
+	for i, r := range rs {
+		strs[i] = string(r)
+	}
+	return strs
+}
+
+// highlight returns the escaped HTML of a and b, where runes out of the longest common subsequence are highlighted. If the lines are too long, all runes are highlighted.
+func highlight(a, b string) (ha, hb string) {
+	ra, rb := []rune(a), []rune(b)
+	var matA, matB []int
+	if int64(len(ra))*int64(len(rb)) > int64(maxHighlightCells) {
+		matA, matB = make([]int, len(ra)), make([]int, len(rb))
+		for i := range matA {
+			matA[i] = -1
+		}
+		for j := range matB {
+			matB[j] = -1
+		}
+	} else {
+		matA, matB = diff.Myers(runeStrings(ra), runeStrings(rb))
+	}
+
+	render := func(rs []rune, mat []int) string {
+		var sb strings.Builder
+		for k := 0; k < len(rs); {
+			hl := mat[k] < 0
+			l := k
+			for k < len(rs) && (mat[k] < 0) == hl {
+				k++
+			}
+			s := html.EscapeString(string(rs[l:k]))
+			if hl {
+				s = `<span class="hl">` + s + `</span>`
+			}
+			sb.WriteString(s)
+		}
+		return sb.String()
+	}
+
+	return render(ra, matA), render(rb, matB)
+}
+
+type writer struct {
+	*bufio.Writer
+	a, b []string
+	opts *Options
+}
+
+func (w *writer) cell(n int, cls, content string) {
+	ln := ""
+	if n >= 0 {
+		ln = fmt.Sprint(n + 1)
+	}
+	fmt.Fprintf(w, `<span class="ln">%s</span><span class="%s">%s</span>`, ln, cls, content)
+}
+
+func (w *writer) sideRow(r row) {
+	switch r.kind {
+	case rowEqual:
+		w.WriteString(`<div class="row">`)
+		w.cell(r.iA, "e", html.EscapeString(w.a[r.iA]))
+		w.cell(r.iB, "e", html.EscapeString(w.b[r.iB]))
+	case rowDel:
+		w.WriteString(`<div class="row">`)
+		w.cell(r.iA, "d", html.EscapeString(w.a[r.iA]))
+		w.cell(-1, "", "")
+	case rowIns:
+		w.WriteString(`<div class="row">`)
+		w.cell(-1, "", "")
+		w.cell(r.iB, "i", html.EscapeString(w.b[r.iB]))
+	case rowChange:
+		ha, hb := highlight(w.a[r.iA], w.b[r.iB])
+		w.WriteString(`<div class="row">`)
+		w.cell(r.iA, "d", ha)
+		w.cell(r.iB, "i", hb)
+	}
+	w.WriteString("</div>\n")
+}
+
+func (w *writer) inlineRow(iA, iB int, cls, mk, content string) {
+	lnA, lnB := "", ""
+	if iA >= 0 {
+		lnA = fmt.Sprint(iA + 1)
+	}
+	if iB >= 0 {
+		lnB = fmt.Sprint(iB + 1)
+	}
+	fmt.Fprintf(w, `<div class="row %s"><span class="ln">%s</span><span class="ln">%s</span><span class="mk">%s</span><span>%s</span></div>`+"\n", cls, lnA, lnB, mk, content)
+}
+
+// inlineRows writes rows in the inline mode. Deleted lines of a region are written before inserted ones.
+func (w *writer) inlineRows(rs []row) {
+	for k := 0; k < len(rs); {
+		if rs[k].kind == rowEqual {
+			w.inlineRow(rs[k].iA, rs[k].iB, "e", " ", html.EscapeString(w.a[rs[k].iA]))
+			k++
+			continue
+		}
+
+		l := k
+		for k < len(rs) && rs[k].kind != rowEqual {
+			k++
+		}
+		hbs := make([]string, k-l)
+		for p := l; p < k; p++ {
+			if r := rs[p]; r.kind == rowDel {
+				w.inlineRow(r.iA, -1, "d", "-", html.EscapeString(w.a[r.iA]))
+			} else if r.kind == rowChange {
+				var ha string
+				ha, hbs[p-l] = highlight(w.a[r.iA], w.b[r.iB])
+				w.inlineRow(r.iA, -1, "d", "-", ha)
+			}
+		}
+		for p := l; p < k; p++ {
+			if r := rs[p]; r.kind == rowIns {
+				w.inlineRow(-1, r.iB, "i", "+", html.EscapeString(w.b[r.iB]))
+			} else if r.kind == rowChange {
+				w.inlineRow(-1, r.iB, "i", "+", hbs[p-l])
+			}
+		}
+	}
+}
+
+func (w *writer) rows(rs []row) {
+	if w.opts.Mode == Inline {
+		w.inlineRows(rs)
+		return
+	}
+	for _, r := range rs {
+		w.sideRow(r)
+	}
+}
+
+/*
+Write writes the HTML report of the difference between lines a and b to w. opts can be nil for default options.
+*/
+func Write(out io.Writer, a, b []string, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	algo := opts.Algorithm
+	if algo == nil {
+		algo = diff.Histogram
+	}
+	context := opts.Context
+	if context == 0 {
+		context = 3
+	}
+	title := opts.Title
+	if title == "" {
+		title = opts.NameA + " vs " + opts.NameB
+	}
+
+	matA, matB := algo(a, b)
+	rs := rows(matA, matB)
+
+	w := &writer{Writer: bufio.NewWriter(out), a: a, b: b, opts: opts}
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n", html.EscapeString(title), css)
+	fmt.Fprintf(w, "<h1>%s</h1>\n", html.EscapeString(title))
+	mode := "side"
+	if opts.Mode == Inline {
+		mode = "inline"
+	}
+	fmt.Fprintf(w, "<div class=\"diff %s\">\n", mode)
+	if opts.Mode == SideBySide && (opts.NameA != "" || opts.NameB != "") {
+		w.WriteString(`<div class="row"><span class="ln"></span><b>` + html.EscapeString(opts.NameA) + `</b><span class="ln"></span><b>` + html.EscapeString(opts.NameB) + "</b></div>\n")
+	}
+
+	for k := 0; k < len(rs); {
+		if rs[k].kind != rowEqual {
+			l := k
+			for k < len(rs) && rs[k].kind != rowEqual {
+				k++
+			}
+			w.rows(rs[l:k])
+			continue
+		}
+
+		l := k
+		for k < len(rs) && rs[k].kind == rowEqual {
+			k++
+		}
+		// rows in [s, e) are collapsed
+		s, e := l, k
+		if context > 0 {
+			if l > 0 {
+				s += context
+			}
+			if k < len(rs) {
+				e -= context
+			}
+		}
+		if context < 0 || e-s <= 1 {
+			w.rows(rs[l:k])
+			continue
+		}
+		w.rows(rs[l:s])
+		fmt.Fprintf(w, "<details><summary>%d unchanged lines</summary>\n", e-s)
+		w.rows(rs[s:e])
+		w.WriteString("</details>\n")
+		w.rows(rs[e:k])
+	}
+
+	w.WriteString("</div>\n</body>\n</html>\n")
+
+	return w.Flush()
+}
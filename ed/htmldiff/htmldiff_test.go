@@ -0,0 +1,63 @@
+package htmldiff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	ha, hb := highlight("a<b>c", "a<x>cd")
+	assert.Equal(t, "ha", ha, `a&lt;<span class="hl">b</span>&gt;c`)
+	assert.Equal(t, "hb", hb, `a&lt;<span class="hl">x</span>&gt;c<span class="hl">d</span>`)
+}
+
+func TestHighlight_Long(t *testing.T) {
+	a := strings.Repeat("var x=1;", 12500)
+	b := strings.Replace(a, "x=1", "y=2", 1)
+	ha, hb := highlight(a, b)
+	assert.Equal(t, "ha", ha, `<span class="hl">`+a+`</span>`)
+	assert.Equal(t, "hb", hb, `<span class="hl">`+b+`</span>`)
+
+	// short lines are still highlighted at rune level
+	ha, _ = highlight(a[:80], b[:80])
+	assert.Equal(t, "short ha", ha, `var <span class="hl">x</span>=<span class="hl">1</span>;`+a[8:80])
+}
+
+func TestWrite(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		a = append(a, fmt.Sprintf("line %d", i))
+		b = append(b, fmt.Sprintf("line %d", i))
+	}
+	a[10] = "old line"
+	b[10] = "new line"
+	b = append(b, "<end>")
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, a, b, &Options{NameA: "a.txt", NameB: "b.txt"}))
+	page := buf.String()
+	assert.True(t, "title", strings.Contains(page, "<title>a.txt vs b.txt</title>"))
+	assert.True(t, "no external assets", !strings.Contains(page, "src=") && !strings.Contains(page, "href="))
+	assert.Equal(t, "details", strings.Count(page, "<details>"), 2)
+	assert.True(t, "collapsed head", strings.Contains(page, "<summary>7 unchanged lines</summary>"))
+	assert.True(t, "collapsed tail", strings.Contains(page, "<summary>3 unchanged lines</summary>"))
+	assert.True(t, "highlight", strings.Contains(page, `<span class="d"><span class="hl">old</span> line</span>`))
+	assert.True(t, "escaped", strings.Contains(page, "&lt;end&gt;"))
+
+	buf.Reset()
+	assert.NoError(t, Write(&buf, a, b, &Options{Mode: Inline, Context: -1}))
+	page = buf.String()
+	assert.Equal(t, "details", strings.Count(page, "<details>"), 0)
+	assert.True(t, "inline", strings.Contains(page, `<div class="diff inline">`))
+	assert.True(t, "deleted", strings.Contains(page, `<div class="row d"><span class="ln">11</span><span class="ln"></span><span class="mk">-</span><span><span class="hl">old</span> line</span></div>`))
+	assert.True(t, "inserted", strings.Contains(page, `<div class="row i"><span class="ln"></span><span class="ln">21</span><span class="mk">+</span><span>&lt;end&gt;</span></div>`))
+}
+
+func TestRows(t *testing.T) {
+	rs := rows([]int{0, -1, -1, 2}, []int{0, -1, 3})
+	assert.StringEqual(t, "rows", rs, []row{{rowEqual, 0, 0}, {rowChange, 1, 1}, {rowDel, 2, -1}, {rowEqual, 3, 2}})
+}
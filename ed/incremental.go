@@ -0,0 +1,117 @@
+package ed
+
+/*
+Incremental calculates the edit-distance between a fixed list A and a list B growing or shrinking at its end, e.g. the text being typed. Elements of B are referred by indexes in cost functions, so the cost functions must be able to handle the index of an element as soon as it is pushed.
+
+Each Push or Pop takes O(m) time where m is the length of A. All DP columns are kept for Pop, so the space complexity is O(mn) where n is the length of B.
+*/
+type Incremental struct {
+	lenA         int
+	costOfChange func(iA, iB int) int
+	costOfDel    func(iA int) int
+	costOfIns    func(iB int) int
+
+	// cols[j][i] is the edit-distance between A[:i] and B[:j]
+	cols [][]int
+}
+
+// NewIncrementalF returns an Incremental with an empty B, whose costs are defined by functions.
+func NewIncrementalF(lenA int, costOfChange func(iA, iB int) int, costOfDel func(iA int) int, costOfIns func(iB int) int) *Incremental {
+	col := make([]int, lenA+1)
+	for i := 1; i <= lenA; i++ {
+		col[i] = addCost(col[i-1], costOfDel(i-1))
+	}
+	return &Incremental{
+		lenA:         lenA,
+		costOfChange: costOfChange,
+		costOfDel:    costOfDel,
+		costOfIns:    costOfIns,
+		cols:         [][]int{col},
+	}
+}
+
+// NewIncremental returns an Incremental with an empty B, whose costs are defined by in. in.LenB is not used.
+func NewIncremental(in Interface) *Incremental {
+	return NewIncrementalF(in.LenA(), in.CostOfChange, in.CostOfDel, in.CostOfIns)
+}
+
+// LenB returns the current length of B.
+func (inc *Incremental) LenB() int {
+	return len(inc.cols) - 1
+}
+
+// Distance returns the edit-distance between A and the current B.
+func (inc *Incremental) Distance() int {
+	return inc.cols[len(inc.cols)-1][inc.lenA]
+}
+
+// Push appends the element at index LenB() to B, and returns the new distance.
+func (inc *Incremental) Push() int {
+	j := inc.LenB()
+	prev := inc.cols[j]
+	col := make([]int, inc.lenA+1)
+	col[0] = addCost(prev[0], inc.costOfIns(j))
+	for i := 1; i <= inc.lenA; i++ {
+		mn := min(addCost(prev[i], inc.costOfIns(j)), addCost(col[i-1], inc.costOfDel(i-1))) // insert & delete
+		col[i] = min(mn, addCost(prev[i-1], inc.costOfChange(i-1, j)))                       // change/matched
+	}
+	inc.cols = append(inc.cols, col)
+
+	return col[inc.lenA]
+}
+
+// Pop removes the last element of B, and returns the new distance. It does nothing if B is empty.
+func (inc *Incremental) Pop() int {
+	if len(inc.cols) > 1 {
+		inc.cols[len(inc.cols)-1] = nil
+		inc.cols = inc.cols[:len(inc.cols)-1]
+	}
+
+	return inc.Distance()
+}
+
+/*
+StringIncremental calculates the edit-distance as ed.String does, between a fixed string A and a string B changing at its end.
+*/
+type StringIncremental struct {
+	a, b []rune
+	inc  *Incremental
+}
+
+// NewStringIncremental returns a StringIncremental with an empty B. a must be UTF-8 encoded.
+func NewStringIncremental(a string) *StringIncremental {
+	s := &StringIncremental{a: []rune(a)}
+	s.inc = NewIncrementalF(len(s.a), func(iA, iB int) int {
+		return Ternary(s.a[iA] == s.b[iB], 0, 1)
+	}, ConstCost(1), ConstCost(1))
+	return s
+}
+
+// B returns the current B.
+func (s *StringIncremental) B() string {
+	return string(s.b)
+}
+
+// Distance returns the edit-distance between A and the current B.
+func (s *StringIncremental) Distance() int {
+	return s.inc.Distance()
+}
+
+// Append appends the runes of str to B, and returns the new distance.
+func (s *StringIncremental) Append(str string) int {
+	for _, r := range str {
+		s.b = append(s.b, r)
+		s.inc.Push()
+	}
+
+	return s.inc.Distance()
+}
+
+// Pop removes the last rune of B, and returns the new distance. It does nothing if B is empty.
+func (s *StringIncremental) Pop() int {
+	if len(s.b) > 0 {
+		s.b = s.b[:len(s.b)-1]
+	}
+
+	return s.inc.Pop()
+}
@@ -0,0 +1,62 @@
+package ed
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestStringIncremental(t *testing.T) {
+	s := NewStringIncremental("kitten")
+	assert.Equal(t, "Distance()", s.Distance(), 6)
+	assert.Equal(t, "Append(s)", s.Append("s"), 6)
+	assert.Equal(t, "Append(itting)", s.Append("itting"), 3)
+	assert.Equal(t, "B()", s.B(), "sitting")
+	assert.Equal(t, "Pop()", s.Pop(), 2)
+	assert.Equal(t, "B()", s.B(), "sittin")
+	for s.B() != "" {
+		s.Pop()
+	}
+	assert.Equal(t, "Pop() of an empty B", s.Pop(), 6)
+
+	s = NewStringIncremental("héllo")
+	assert.Equal(t, "Append(hello)", s.Append("hello"), 1)
+}
+
+func TestIncremental_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	a := []rune("abcabd")
+	var b []rune
+	costOfChange := func(iA, iB int) int {
+		return Ternary(a[iA] == b[iB], 0, 3)
+	}
+	inc := NewIncremental(&funcInterface{len(a), 0, costOfChange, ConstCost(2), ConstCost(1)})
+	for k := 0; k < 500; k++ {
+		if len(b) > 0 && rnd.Intn(3) == 0 {
+			b = b[:len(b)-1]
+			inc.Pop()
+		} else {
+			b = append(b, rune('a'+rnd.Intn(4)))
+			inc.Push()
+		}
+		exp := EditDistanceF(len(a), len(b), costOfChange, ConstCost(2), ConstCost(1))
+		assert.Equal(t, fmt.Sprintf("Distance() with B = %s", string(b)), inc.Distance(), exp)
+		assert.Equal(t, "LenB()", inc.LenB(), len(b))
+	}
+}
+
+func ExampleStringIncremental() {
+	s := NewStringIncremental("gopher")
+	for _, r := range "goph" {
+		fmt.Println(s.Append(string(r)))
+	}
+	fmt.Println(s.Pop())
+	// Output:
+	// 5
+	// 4
+	// 3
+	// 2
+	// 3
+}
@@ -0,0 +1,202 @@
+/*
+join package finds all pairs of strings within an edit-distance threshold k among large sets, by the partition-based Pass-Join algorithm.
+
+Each indexed string is partitioned into k+1 segments. If ed.String(r, s) <= k, at most k segments of s are edited, so at least one segment appears unchanged in r, shifted by at most (k+|r|-|s|)/2 runes. Strings sharing such a segment are candidates, which are verified by ed.StringBounded. Strings shorter than k+1 runes cannot be partitioned and are verified directly with all strings of close lengths.
+
+Probing is done in parallel after the index is built, and results are streamed through a callback.
+*/
+package join
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Pair is a pair of strings within the threshold. A and B are indexes in the input lists, and A < B for a self-join.
+type Pair struct {
+	A, B, Distance int
+}
+
+type segKey struct {
+	l, i int
+	seg  string
+}
+
+// index is the segment index of strings.
+type index struct {
+	k     int
+	runes [][]rune
+	// segs[key] are ids of strings of length key.l whose key.i-th segment is key.seg
+	segs map[segKey][]int
+	// short[l] are ids of strings of length l <= k
+	short map[int][]int
+}
+
+// segment returns the start and the length of the i-th of k+1 segments of a string of length l. The last l%(k+1) segments are one rune longer.
+func segment(l, k, i int) (start, length int) {
+	n := k + 1
+	base, long := l/n, l%n
+	start = i * base
+	if short := n - long; i > short {
+		start += i - short
+	}
+	length = base
+	if i >= n-long {
+		length++
+	}
+	return start, length
+}
+
+func newIndex(strs []string, k int) *index {
+	idx := &index{k: k, runes: make([][]rune, len(strs)), segs: make(map[segKey][]int), short: make(map[int][]int)}
+	for id, s := range strs {
+		rs := []rune(s)
+		idx.runes[id] = rs
+		l := len(rs)
+		if l <= k {
+			idx.short[l] = append(idx.short[l], id)
+			continue
+		}
+		for i := 0; i <= k; i++ {
+			p, sl := segment(l, k, i)
+			key := segKey{l, i, string(rs[p : p+sl])}
+			idx.segs[key] = append(idx.segs[key], id)
+		}
+	}
+	return idx
+}
+
+/*
+probe calls fn with ids of candidate strings of lengths from lo to hi, for r. An id may be passed more than once.
+*/
+func (idx *index) probe(r []rune, lo, hi int, fn func(id int)) {
+	k, lr := idx.k, len(r)
+	for l := lo; l <= hi; l++ {
+		for _, id := range idx.short[l] {
+			fn(id)
+		}
+		if l <= k {
+			continue
+		}
+		delta := lr - l
+		// the shift of an unchanged segment is within [ceil((delta-k)/2), floor((delta+k)/2)]
+		dLo, dHi := -((k - delta) / 2), (delta+k)/2
+		for i := 0; i <= k; i++ {
+			p, sl := segment(l, k, i)
+			from, to := p+dLo, p+dHi
+			if from < 0 {
+				from = 0
+			}
+			if to > lr-sl {
+				to = lr - sl
+			}
+			for q := from; q <= to; q++ {
+				for _, id := range idx.segs[segKey{l, i, string(r[q : q+sl])}] {
+					fn(id)
+				}
+			}
+		}
+	}
+}
+
+// parallel calls f(i) for i in [0, n) on workers goroutines, where workers <= 0 means runtime.GOMAXPROCS(0).
+func parallel(n, workers int, f func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var wg sync.WaitGroup
+	next := int64(0)
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1)) - 1
+				if i >= n {
+					return
+				}
+				f(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+/*
+SelfJoin calls fn with every pair of strings in strs within edit-distance k, where Pair.A < Pair.B. Strings are probed on workers goroutines, and workers <= 0 means runtime.GOMAXPROCS(0). fn is never called concurrently, but pairs come in no particular order.
+
+Each string is probed against strings of the same or shorter lengths that are before it in the order of lengths and indexes, so every pair is verified at most once.
+*/
+func SelfJoin(strs []string, k int, workers int, fn func(p Pair)) {
+	idx := newIndex(strs, k)
+	// rank[id] is the position of id in the order of lengths and indexes
+	order := make([]int, len(strs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return len(idx.runes[order[i]]) < len(idx.runes[order[j]]) })
+	rank := make([]int, len(strs))
+	for i, id := range order {
+		rank[id] = i
+	}
+
+	var mu sync.Mutex
+	parallel(len(strs), workers, func(r int) {
+		rr := idx.runes[r]
+		seen := make(map[int]bool)
+		var pairs []Pair
+		idx.probe(rr, len(rr)-k, len(rr), func(s int) {
+			if rank[s] >= rank[r] || seen[s] {
+				return
+			}
+			seen[s] = true
+			if d := ed.StringBounded(strs[r], strs[s], k); d <= k {
+				if s < r {
+					pairs = append(pairs, Pair{s, r, d})
+				} else {
+					pairs = append(pairs, Pair{r, s, d})
+				}
+			}
+		})
+		if len(pairs) > 0 {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, p := range pairs {
+				fn(p)
+			}
+		}
+	})
+}
+
+/*
+Join calls fn with every pair of a string in rs and a string in ss within edit-distance k, where Pair.A indexes rs and Pair.B indexes ss. ss is indexed and strings of rs are probed on workers goroutines, and workers <= 0 means runtime.GOMAXPROCS(0). fn is never called concurrently, but pairs come in no particular order.
+*/
+func Join(rs, ss []string, k int, workers int, fn func(p Pair)) {
+	idx := newIndex(ss, k)
+	var mu sync.Mutex
+	parallel(len(rs), workers, func(r int) {
+		rr := []rune(rs[r])
+		seen := make(map[int]bool)
+		var pairs []Pair
+		idx.probe(rr, len(rr)-k, len(rr)+k, func(s int) {
+			if seen[s] {
+				return
+			}
+			seen[s] = true
+			if d := ed.StringBounded(rs[r], ss[s], k); d <= k {
+				pairs = append(pairs, Pair{r, s, d})
+			}
+		})
+		if len(pairs) > 0 {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, p := range pairs {
+				fn(p)
+			}
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package join
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+func TestSegment(t *testing.T) {
+	test := func(l, k int, exp [][2]int) {
+		var act [][2]int
+		for i := 0; i <= k; i++ {
+			p, sl := segment(l, k, i)
+			act = append(act, [2]int{p, sl})
+		}
+		assert.StringEqual(t, fmt.Sprintf("segment(%d, %d)", l, k), act, exp)
+	}
+
+	test(6, 2, [][2]int{{0, 2}, {2, 2}, {4, 2}})
+	test(7, 2, [][2]int{{0, 2}, {2, 2}, {4, 3}})
+	test(8, 2, [][2]int{{0, 2}, {2, 3}, {5, 3}})
+	test(3, 0, [][2]int{{0, 3}})
+}
+
+func randStrings(rnd *rand.Rand, n int) []string {
+	strs := make([]string, n)
+	for i := range strs {
+		rs := make([]rune, rnd.Intn(12))
+		for j := range rs {
+			rs[j] = 'a' + rune(rnd.Intn(3))
+		}
+		strs[i] = string(rs)
+	}
+	return strs
+}
+
+func sortPairs(pairs []Pair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+}
+
+func TestSelfJoin(t *testing.T) {
+	strs := randStrings(rand.New(rand.NewSource(1)), 400)
+	for k := 0; k <= 3; k++ {
+		var exp []Pair
+		for a := range strs {
+			for b := a + 1; b < len(strs); b++ {
+				if d := ed.String(strs[a], strs[b]); d <= k {
+					exp = append(exp, Pair{a, b, d})
+				}
+			}
+		}
+		for _, workers := range []int{1, 4, 0} {
+			var act []Pair
+			SelfJoin(strs, k, workers, func(p Pair) {
+				act = append(act, p)
+			})
+			sortPairs(act)
+			assert.StringEqual(t, fmt.Sprintf("SelfJoin(%d, %d)", k, workers), act, exp)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	rs, ss := randStrings(rnd, 200), randStrings(rnd, 300)
+	for k := 0; k <= 3; k++ {
+		var exp []Pair
+		for a := range rs {
+			for b := range ss {
+				if d := ed.String(rs[a], ss[b]); d <= k {
+					exp = append(exp, Pair{a, b, d})
+				}
+			}
+		}
+		for _, workers := range []int{1, 4} {
+			var act []Pair
+			Join(rs, ss, k, workers, func(p Pair) {
+				act = append(act, p)
+			})
+			sortPairs(act)
+			assert.StringEqual(t, fmt.Sprintf("Join(%d, %d)", k, workers), act, exp)
+		}
+	}
+}
+
+func ExampleSelfJoin() {
+	names := []string{"Jonathan", "Johnathan", "Jon", "Jonathon", "John", "Joan"}
+	var pairs []Pair
+	SelfJoin(names, 1, 0, func(p Pair) {
+		pairs = append(pairs, p)
+	})
+	sortPairs(pairs)
+	for _, p := range pairs {
+		fmt.Println(names[p.A], names[p.B], p.Distance)
+	}
+	// Output:
+	// Jonathan Johnathan 1
+	// Jonathan Jonathon 1
+	// Jon John 1
+	// Jon Joan 1
+	// John Joan 1
+}
@@ -0,0 +1,373 @@
+/*
+jsondiff package computes structural differences between JSON values decoded by encoding/json, i.e. values of types nil, bool, float64, json.Number, string, []interface{} and map[string]interface{}.
+
+Diff returns a list of operations, which can be marshaled as an RFC 6902 JSON Patch with Patch, and applied to a value with Apply. By default arrays are compared index by index. If Options.AlignArrays is set, elements are aligned with ed.EditDistanceFFull, where the cost of changing an element is the tree edit-distance (see the ed/tree package) between the elements.
+*/
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/daviddengcn/go-algs/ed"
+	"github.com/daviddengcn/go-algs/ed/tree"
+)
+
+// Operation names of RFC 6902.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// Op is an operation of a JSON Patch.
+type Op struct {
+	// One of OpAdd, OpRemove and OpReplace
+	Op string
+	// JSON Pointer (RFC 6901) of the target location
+	Path string
+	// The new value for OpAdd and OpReplace
+	Value interface{}
+	// The old value for OpRemove and OpReplace. Not included in the patch.
+	OldValue interface{}
+}
+
+// MarshalJSON implements json.Marshaler. OldValue is omitted, and Value is omitted for OpRemove.
+func (op Op) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"op":`)
+	b, _ := json.Marshal(op.Op)
+	buf.Write(b)
+	buf.WriteString(`,"path":`)
+	b, _ = json.Marshal(op.Path)
+	buf.Write(b)
+	if op.Op != OpRemove {
+		buf.WriteString(`,"value":`)
+		b, err := json.Marshal(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// String returns a readable form of the operation, e.g. `replace /a/0: 1 -> 2`.
+func (op Op) String() string {
+	enc := func(v interface{}) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+	switch op.Op {
+	case OpAdd:
+		return fmt.Sprintf("%s %s: %s", op.Op, op.Path, enc(op.Value))
+	case OpRemove:
+		return fmt.Sprintf("%s %s: %s", op.Op, op.Path, enc(op.OldValue))
+	}
+	return fmt.Sprintf("%s %s: %s -> %s", op.Op, op.Path, enc(op.OldValue), enc(op.Value))
+}
+
+// Options controls the behavior of Diff.
+type Options struct {
+	// If AlignArrays is true, elements of arrays are aligned by the ed engine, otherwise arrays are compared index by index.
+	AlignArrays bool
+}
+
+/*
+Diff returns the operations transforming a into b. Operations are in order, i.e. the path of each operation refers to the value after applying all operations before it. Keys of objects are processed in sorted order so the result is deterministic. opts can be nil for default options.
+*/
+func Diff(a, b interface{}, opts *Options) []Op {
+	if opts == nil {
+		opts = &Options{}
+	}
+	d := &differ{opts: opts}
+	d.diff("", a, b)
+
+	return d.ops
+}
+
+/*
+Patch returns the RFC 6902 JSON Patch document of the operations.
+*/
+func Patch(ops []Op) ([]byte, error) {
+	if ops == nil {
+		ops = []Op{}
+	}
+	return json.Marshal(ops)
+}
+
+type differ struct {
+	opts *Options
+	ops  []Op
+}
+
+func (d *differ) add(op string, path string, oldValue, value interface{}) {
+	d.ops = append(d.ops, Op{Op: op, Path: path, Value: value, OldValue: oldValue})
+}
+
+func (d *differ) diff(path string, a, b interface{}) {
+	switch va := a.(type) {
+	case map[string]interface{}:
+		if vb, ok := b.(map[string]interface{}); ok {
+			d.diffObject(path, va, vb)
+			return
+		}
+
+	case []interface{}:
+		if vb, ok := b.([]interface{}); ok {
+			if d.opts.AlignArrays {
+				d.diffArrayAligned(path, va, vb)
+			} else {
+				d.diffArray(path, va, vb)
+			}
+			return
+		}
+	}
+
+	if !equal(a, b) {
+		d.add(OpReplace, path, a, b)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (d *differ) diffObject(path string, a, b map[string]interface{}) {
+	for _, k := range sortedKeys(a) {
+		if vb, ok := b[k]; ok {
+			d.diff(path+"/"+escape(k), a[k], vb)
+		} else {
+			d.add(OpRemove, path+"/"+escape(k), a[k], nil)
+		}
+	}
+	for _, k := range sortedKeys(b) {
+		if _, ok := a[k]; !ok {
+			d.add(OpAdd, path+"/"+escape(k), nil, b[k])
+		}
+	}
+}
+
+func (d *differ) diffArray(path string, a, b []interface{}) {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		d.diff(path+"/"+strconv.Itoa(i), a[i], b[i])
+	}
+	// remove from the end so that indexes are valid
+	for i := len(a) - 1; i >= n; i-- {
+		d.add(OpRemove, path+"/"+strconv.Itoa(i), a[i], nil)
+	}
+	for i := n; i < len(b); i++ {
+		d.add(OpAdd, path+"/"+strconv.Itoa(i), nil, b[i])
+	}
+}
+
+func (d *differ) diffArrayAligned(path string, a, b []interface{}) {
+	ta, tb := make([]*tree.Node, len(a)), make([]*tree.Node, len(b))
+	sa, sb := make([]int, len(a)), make([]int, len(b))
+	for i, v := range a {
+		ta[i], sa[i] = toTree(v)
+	}
+	for j, v := range b {
+		tb[j], sb[j] = toTree(v)
+	}
+	_, matA, matB := ed.EditDistanceFFull(len(a), len(b), func(iA, iB int) int {
+		return tree.Distance(tree.NewLabelTrees(ta[iA], tb[iB]))
+	}, func(iA int) int {
+		return sa[iA]
+	}, func(iB int) int {
+		return sb[iB]
+	})
+
+	// k is the index in the array being transformed
+	i, j, k := 0, 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && matA[i] < 0:
+			d.add(OpRemove, path+"/"+strconv.Itoa(k), a[i], nil)
+			i++
+		case j < len(b) && matB[j] < 0:
+			d.add(OpAdd, path+"/"+strconv.Itoa(k), nil, b[j])
+			j++
+			k++
+		default:
+			d.diff(path+"/"+strconv.Itoa(k), a[i], b[j])
+			i++
+			j++
+			k++
+		}
+	}
+}
+
+// toTree converts a JSON value into a labeled tree and returns the number of nodes. Each member of an object is a node labeled with the key, whose only child is the value.
+func toTree(v interface{}) (*tree.Node, int) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		nd, size := &tree.Node{Label: "{}"}, 1
+		for _, k := range sortedKeys(v) {
+			c, sz := toTree(v[k])
+			nd.Children = append(nd.Children, &tree.Node{Label: strconv.Quote(k) + ":", Children: []*tree.Node{c}})
+			size += sz + 1
+		}
+		return nd, size
+
+	case []interface{}:
+		nd, size := &tree.Node{Label: "[]"}, 1
+		for _, e := range v {
+			c, sz := toTree(e)
+			nd.Children = append(nd.Children, c)
+			size += sz
+		}
+		return nd, size
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return &tree.Node{Label: fmt.Sprint(v)}, 1
+	}
+	return &tree.Node{Label: string(b)}, 1
+}
+
+func equal(a, b interface{}) bool {
+	if na, ok := a.(json.Number); ok {
+		if nb, ok := b.(json.Number); ok {
+			fa, errA := na.Float64()
+			fb, errB := nb.Float64()
+			if errA == nil && errB == nil {
+				return fa == fb
+			}
+		}
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func escape(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
+
+func unescape(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
+
+// ErrInvalidPath is returned by Apply if the path of an operation does not exist.
+var ErrInvalidPath = errors.New("jsondiff: invalid path")
+
+/*
+Apply applies the operations to doc and returns the result. Only OpAdd, OpRemove and OpReplace are supported. Objects and arrays in doc are modified in place if possible.
+*/
+func Apply(doc interface{}, ops []Op) (interface{}, error) {
+	for _, op := range ops {
+		var err error
+		if doc, err = apply(doc, op); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+func apply(doc interface{}, op Op) (interface{}, error) {
+	if op.Op != OpAdd && op.Op != OpRemove && op.Op != OpReplace {
+		return nil, fmt.Errorf("jsondiff: unsupported operation %q", op.Op)
+	}
+	if op.Path == "" {
+		if op.Op == OpRemove {
+			return nil, nil
+		}
+		return op.Value, nil
+	}
+	if op.Path[0] != '/' {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPath, op.Path)
+	}
+
+	tokens := strings.Split(op.Path[1:], "/")
+	for i := range tokens {
+		tokens[i] = unescape(tokens[i])
+	}
+	v, err := applyAt(doc, tokens, op)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, op.Path)
+	}
+
+	return v, nil
+}
+
+// applyAt applies the operation at the path of tokens relative to v, and returns the new v.
+func applyAt(v interface{}, tokens []string, op Op) (interface{}, error) {
+	tk := tokens[0]
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(tokens) > 1 {
+			c, ok := v[tk]
+			if !ok {
+				return nil, ErrInvalidPath
+			}
+			c, err := applyAt(c, tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			v[tk] = c
+			return v, nil
+		}
+		if _, ok := v[tk]; !ok && op.Op != OpAdd {
+			return nil, ErrInvalidPath
+		}
+		if op.Op == OpRemove {
+			delete(v, tk)
+		} else {
+			v[tk] = op.Value
+		}
+		return v, nil
+
+	case []interface{}:
+		if len(tokens) == 1 && op.Op == OpAdd && tk == "-" {
+			return append(v, op.Value), nil
+		}
+		idx, err := strconv.Atoi(tk)
+		if err != nil || idx < 0 || idx > len(v) || idx == len(v) && (len(tokens) > 1 || op.Op != OpAdd) {
+			return nil, ErrInvalidPath
+		}
+		if len(tokens) > 1 {
+			c, err := applyAt(v[idx], tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			v[idx] = c
+			return v, nil
+		}
+		switch op.Op {
+		case OpAdd:
+			v = append(v, nil)
+			copy(v[idx+1:], v[idx:])
+			v[idx] = op.Value
+		case OpRemove:
+			v = append(v[:idx], v[idx+1:]...)
+		default:
+			v[idx] = op.Value
+		}
+		return v, nil
+	}
+
+	return nil, ErrInvalidPath
+}
@@ -0,0 +1,67 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func decode(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func TestDiff(t *testing.T) {
+	test := func(a, b string, opts *Options, patch string) {
+		ops := Diff(decode(a), decode(b), opts)
+		p, err := Patch(ops)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("Patch from %s to %s", a, b), string(p), patch)
+
+		// applying the patch gets b
+		act, err := Apply(decode(a), ops)
+		assert.NoError(t, err)
+		assert.True(t, fmt.Sprintf("Apply(%s, %s) = %v", a, p, act), reflect.DeepEqual(act, decode(b)))
+	}
+
+	test(`1`, `1`, nil, `[]`)
+	test(`1`, `"a"`, nil, `[{"op":"replace","path":"","value":"a"}]`)
+	test(`{"a":1,"b":2}`, `{"b":3,"c":null}`, nil,
+		`[{"op":"remove","path":"/a"},{"op":"replace","path":"/b","value":3},{"op":"add","path":"/c","value":null}]`)
+	test(`{"a/b":{"m~n":1}}`, `{"a/b":{"m~n":2}}`, nil, `[{"op":"replace","path":"/a~1b/m~0n","value":2}]`)
+	test(`[1,2,3]`, `[1,4]`, nil, `[{"op":"replace","path":"/1","value":4},{"op":"remove","path":"/2"}]`)
+	test(`[1,2,3]`, `[0,1,2,3]`, nil,
+		`[{"op":"replace","path":"/0","value":0},{"op":"replace","path":"/1","value":1},{"op":"replace","path":"/2","value":2},{"op":"add","path":"/3","value":3}]`)
+	test(`[1,2,3]`, `[0,1,2,3]`, &Options{AlignArrays: true}, `[{"op":"add","path":"/0","value":0}]`)
+	test(`[1,2,3,4]`, `[2,3,5,4]`, &Options{AlignArrays: true},
+		`[{"op":"remove","path":"/0"},{"op":"add","path":"/2","value":5}]`)
+	test(`[{"id":1,"v":"a"},{"id":2,"v":"b"}]`, `[{"id":0},{"id":1,"v":"a"},{"id":2,"v":"c"}]`, &Options{AlignArrays: true},
+		`[{"op":"add","path":"/0","value":{"id":0}},{"op":"replace","path":"/2/v","value":"c"}]`)
+}
+
+func TestApply_Error(t *testing.T) {
+	_, err := Apply(decode(`{"a":[1]}`), []Op{{Op: OpReplace, Path: "/a/1", Value: 2}})
+	assert.True(t, "errors.Is(err, ErrInvalidPath)", errors.Is(err, ErrInvalidPath))
+	_, err = Apply(decode(`{"a":[1]}`), []Op{{Op: OpRemove, Path: "/b"}})
+	assert.True(t, "errors.Is(err, ErrInvalidPath)", errors.Is(err, ErrInvalidPath))
+	_, err = Apply(decode(`{}`), []Op{{Op: "move", Path: "/b"}})
+	assert.Error(t, err)
+}
+
+func ExampleDiff() {
+	a := decode(`{"name":"app","ports":[80,443]}`)
+	b := decode(`{"name":"app","ports":[8080,80,443],"debug":true}`)
+	for _, op := range Diff(a, b, &Options{AlignArrays: true}) {
+		fmt.Println(op)
+	}
+	// Output:
+	// add /ports/0: 8080
+	// add /debug: true
+}
@@ -0,0 +1,119 @@
+/*
+median package computes the string that best represents a set of strings under an edit-distance.
+
+The set median is the element of the set with the minimum sum of distances to all elements. The generalized median is any string with the minimum sum, which is NP-hard to find, so Median approximates it by perturbing the set median with single edits while the sum decreases.
+
+For consensus of aligned sequences, see Alignment.Consensus in the ed/msa package.
+*/
+package median
+
+import (
+	"sort"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Distance returns the distance between two strings. A nil Distance means ed.String.
+type Distance func(a, b string) int
+
+func (d Distance) dist(a, b string) int {
+	if d == nil {
+		return ed.String(a, b)
+	}
+	return d(a, b)
+}
+
+// InterfaceDistance returns a Distance computed by ed.EditDistance of the ed.Interface returned by newIn for each pair of strings.
+func InterfaceDistance(newIn func(a, b string) ed.Interface) Distance {
+	return func(a, b string) int {
+		return ed.EditDistance(newIn(a, b))
+	}
+}
+
+// SumOfDistances returns the sum of distances from m to all strings in strs.
+func SumOfDistances(m string, strs []string, d Distance) int {
+	sum := 0
+	for _, s := range strs {
+		sum += d.dist(m, s)
+	}
+	return sum
+}
+
+/*
+SetMedian returns the index of the set median of strs and its sum of distances. Ties are broken by the lowest index. It returns -1 if strs is empty.
+
+The time complexity is O(k^2 D) where k is the number of strings and D is the time of a distance.
+*/
+func SetMedian(strs []string, d Distance) (index, sum int) {
+	index = -1
+	for i, m := range strs {
+		if s := SumOfDistances(m, strs, d); index < 0 || s < sum {
+			index, sum = i, s
+		}
+	}
+	return index, sum
+}
+
+// alphabet returns the sorted distinct runes in strs.
+func alphabet(strs []string) []rune {
+	set := make(map[rune]bool)
+	for _, s := range strs {
+		for _, r := range s {
+			set[r] = true
+		}
+	}
+	runes := make([]rune, 0, len(set))
+	for r := range set {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
+
+/*
+Median returns an approximate generalized median of strs and its sum of distances. Starting from the set median, each position is perturbed by the best of deleting it, substituting it and inserting before it with any rune in strs, if that decreases the sum. Passes over all positions are repeated until no perturbation improves, so the sum is never larger than that of the set median.
+
+Each pass takes O(l s k D) time where l is the length of the median, s is the size of the alphabet, k is the number of strings and D is the time of a distance.
+*/
+func Median(strs []string, d Distance) (median string, sum int) {
+	index, sum := SetMedian(strs, d)
+	if index < 0 {
+		return "", 0
+	}
+	alpha := alphabet(strs)
+	m := []rune(strs[index])
+
+	try := func(cand []rune, best []rune, bestSum int) ([]rune, int) {
+		if s := SumOfDistances(string(cand), strs, d); s < bestSum {
+			return cand, s
+		}
+		return best, bestSum
+	}
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i <= len(m); i++ {
+			var best []rune
+			bestSum := sum
+			for _, r := range alpha {
+				// insert r before i
+				cand := append(append(append([]rune(nil), m[:i]...), r), m[i:]...)
+				best, bestSum = try(cand, best, bestSum)
+				if i < len(m) && r != m[i] {
+					// substitute m[i] with r
+					cand := append([]rune(nil), m...)
+					cand[i] = r
+					best, bestSum = try(cand, best, bestSum)
+				}
+			}
+			if i < len(m) {
+				// delete m[i]
+				cand := append(append([]rune(nil), m[:i]...), m[i+1:]...)
+				best, bestSum = try(cand, best, bestSum)
+			}
+			if best != nil {
+				m, sum, improved = best, bestSum, true
+			}
+		}
+	}
+	return string(m), sum
+}
@@ -0,0 +1,95 @@
+package median
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+func TestSetMedian(t *testing.T) {
+	test := func(strs []string, index, sum int) {
+		actIndex, actSum := SetMedian(strs, nil)
+		name := fmt.Sprintf("SetMedian(%v)", strs)
+		assert.Equal(t, name+" index", actIndex, index)
+		assert.Equal(t, name+" sum", actSum, sum)
+	}
+
+	test([]string{"abc"}, 0, 0)
+	test([]string{"abc", "abd", "abe"}, 0, 2)
+	test([]string{"kitten", "sitten", "sitting", "kitting"}, 0, 6)
+	test(nil, -1, 0)
+}
+
+func TestMedian(t *testing.T) {
+	test := func(strs []string, median string, sum int) {
+		actMedian, actSum := Median(strs, nil)
+		name := fmt.Sprintf("Median(%v)", strs)
+		assert.Equal(t, name, actMedian, median)
+		assert.Equal(t, name+" sum", actSum, sum)
+		assert.Equal(t, name+" SumOfDistances", SumOfDistances(actMedian, strs, nil), sum)
+		_, setSum := SetMedian(strs, nil)
+		assert.True(t, name+" not worse than the set median", actSum <= setSum)
+	}
+
+	test([]string{"abc"}, "abc", 0)
+	// the median is not in the set
+	test([]string{"xbc", "ayc", "abz"}, "abc", 3)
+	test([]string{"12 Main Street", "12 Main St", "12 Main Str.", "12 Mian Street"}, "12 Main Street", 9)
+	test(nil, "", 0)
+}
+
+func TestInterfaceDistance(t *testing.T) {
+	// substitution costs 3, so it is cheaper to delete and insert
+	d := InterfaceDistance(func(a, b string) ed.Interface {
+		return &runes{a: []rune(a), b: []rune(b)}
+	})
+	assert.Equal(t, "d", d("abc", "abd"), 2)
+
+	m, sum := Median([]string{"xbc", "ayc", "abz"}, d)
+	assert.Equal(t, "sum", sum, SumOfDistances(m, []string{"xbc", "ayc", "abz"}, d))
+}
+
+type runes struct {
+	a, b []rune
+}
+
+// Interface.LenA
+func (r *runes) LenA() int {
+	return len(r.a)
+}
+
+// Interface.LenB
+func (r *runes) LenB() int {
+	return len(r.b)
+}
+
+// Interface.CostOfChange
+func (r *runes) CostOfChange(iA, iB int) int {
+	return ed.Ternary(r.a[iA] == r.b[iB], 0, 3)
+}
+
+// Interface.CostOfDel
+func (r *runes) CostOfDel(iA int) int {
+	return 1
+}
+
+// Interface.CostOfIns
+func (r *runes) CostOfIns(iB int) int {
+	return 1
+}
+
+func ExampleMedian() {
+	addresses := []string{
+		"1600 Amphitheatre Pkwy",
+		"1600 Amphitheater Pkwy",
+		"1600 Amphitheatre Parkway",
+		"1600 Amphiteatre Pkwy",
+	}
+	m, sum := Median(addresses, nil)
+	fmt.Println(m, sum)
+	// Output:
+	// 1600 Amphitheatre Pkwy 6
+}
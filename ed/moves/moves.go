@@ -0,0 +1,127 @@
+/*
+moves package computes the edit-distance with block moves, where moving a contiguous block of elements is an operation besides deleting and inserting single elements. A paragraph moved within a document is then reported as one move instead of a large deletion plus insertion.
+
+Computing the exact edit-distance with moves is NP-hard, so the greedy approximation is used: lines are first aligned by a diff algorithm, then among the deleted and inserted elements, the longest common block is repeatedly taken as a move, as long as moving it is cheaper than deleting and inserting it.
+*/
+package moves
+
+import (
+	"github.com/daviddengcn/go-algs/ed/diff"
+)
+
+// Block is a moved block: a[A:A+Len] is moved to b[B:B+Len].
+type Block struct {
+	A, B, Len int
+}
+
+// Options controls the costs and the alignment. A nil *Options means default values.
+type Options struct {
+	// DelCost and InsCost are the costs of deleting and inserting an element. Both default to 1.
+	DelCost, InsCost int
+
+	// MoveCost returns the cost of moving a block of l elements. The default is a fixed cost of 2, i.e. a block of 2 or more elements is moved rather than deleted and inserted.
+	MoveCost func(l int) int
+
+	// MinLen is the minimum length of moved blocks. The default is 1.
+	MinLen int
+
+	// Algorithm aligns a and b before finding moves. The default is diff.Myers.
+	Algorithm diff.Algorithm
+}
+
+func (opts *Options) withDefaults() Options {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.DelCost <= 0 {
+		o.DelCost = 1
+	}
+	if o.InsCost <= 0 {
+		o.InsCost = 1
+	}
+	if o.MoveCost == nil {
+		o.MoveCost = func(int) int {
+			return 2
+		}
+	}
+	if o.MinLen <= 0 {
+		o.MinLen = 1
+	}
+	if o.Algorithm == nil {
+		o.Algorithm = diff.Myers
+	}
+	return o
+}
+
+/*
+Distance returns the edit-distance with block moves between a and b, the matching, and the moved blocks in the order they are found, i.e. from the longest.
+
+matA and matB are in the same format as ed.EditDistanceFull, including the elements of moved blocks, so the matching is not monotonic if there are moves. Elements of a moved block are equal and adjacent in both lists.
+
+The time complexity is O(kmn) in addition to the alignment, where m and n are the numbers of deleted and inserted elements after the alignment, and k is the number of moves. The space complexity is O(mn).
+*/
+func Distance(a, b []string, opts *Options) (dist int, matA, matB []int, moves []Block) {
+	o := opts.withDefaults()
+	matA, matB = o.Algorithm(a, b)
+
+	// positions of unmatched elements
+	var delA, insB []int
+	for i, j := range matA {
+		if j < 0 {
+			delA = append(delA, i)
+		}
+	}
+	for j, i := range matB {
+		if i < 0 {
+			insB = append(insB, j)
+		}
+	}
+
+	// l[x+1][y+1] is the length of the common block of unmatched elements ending at delA[x] and insB[y]
+	l := make([][]int, len(delA)+1)
+	for x := range l {
+		l[x] = make([]int, len(insB)+1)
+	}
+	for {
+		best, bx, by := 0, 0, 0
+		for x, i := range delA {
+			for y, j := range insB {
+				l[x+1][y+1] = 0
+				if matA[i] >= 0 || matB[j] >= 0 || a[i] != b[j] {
+					continue
+				}
+				n := 1
+				if x > 0 && y > 0 && delA[x-1] == i-1 && insB[y-1] == j-1 {
+					n += l[x][y]
+				}
+				l[x+1][y+1] = n
+				if n > best {
+					best, bx, by = n, x, y
+				}
+			}
+		}
+		if best < o.MinLen || o.MoveCost(best) >= best*(o.DelCost+o.InsCost) {
+			break
+		}
+
+		blk := Block{A: delA[bx] - best + 1, B: insB[by] - best + 1, Len: best}
+		for k := 0; k < best; k++ {
+			matA[blk.A+k], matB[blk.B+k] = blk.B+k, blk.A+k
+		}
+		moves = append(moves, blk)
+		dist += o.MoveCost(best)
+	}
+
+	for _, j := range matA {
+		if j < 0 {
+			dist += o.DelCost
+		}
+	}
+	for _, i := range matB {
+		if i < 0 {
+			dist += o.InsCost
+		}
+	}
+	return dist, matA, matB, moves
+}
@@ -0,0 +1,53 @@
+package moves
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestDistance(t *testing.T) {
+	test := func(a, b string, opts *Options, dist int, moves []Block) {
+		la, lb := strings.Split(a, ""), strings.Split(b, "")
+		actDist, matA, matB, actMoves := Distance(la, lb, opts)
+		name := fmt.Sprintf("Distance(%s, %s)", a, b)
+		assert.Equal(t, name, actDist, dist)
+		assert.StringEqual(t, name+" moves", actMoves, moves)
+		for i, j := range matA {
+			if j >= 0 {
+				assert.Equal(t, fmt.Sprintf("%s matB[matA[%d]]", name, i), matB[j], i)
+				assert.Equal(t, fmt.Sprintf("%s b[matA[%d]]", name, i), lb[j], la[i])
+			}
+		}
+	}
+
+	test("abcdef", "abcdef", nil, 0, nil)
+	test("abcdef", "defabc", nil, 2, []Block{{0, 3, 3}})
+	test("abcxyz", "xyzabc", nil, 2, []Block{{0, 3, 3}})
+	test("abcdef", "abdcef", nil, 2, nil)
+	// abcd and efgh are both longest common subsequences, and Myers keeps abcd
+	test("abcdefgh", "efghXabcd", nil, 3, []Block{{4, 0, 4}})
+	test("abcdefgh", "ghefcdab", nil, 6, []Block{{0, 6, 2}, {2, 4, 2}, {4, 2, 2}})
+	test("", "abc", nil, 3, nil)
+
+	// a length-dependent cost
+	opts := &Options{MoveCost: func(l int) int { return 1 + l/2 }}
+	test("abcdef", "defabc", opts, 2, []Block{{0, 3, 3}})
+	test("abcdefgh", "efghabcd", opts, 3, []Block{{0, 4, 4}})
+
+	// blocks shorter than MinLen are not moved
+	test("abcdef", "defabc", &Options{MinLen: 4}, 6, nil)
+	// moves more expensive than deletes and inserts are not taken
+	test("abcdef", "defabc", &Options{MoveCost: func(int) int { return 6 }}, 6, nil)
+}
+
+func TestDistance_Lines(t *testing.T) {
+	a := []string{"# Title", "Intro.", "## Usage", "Run it.", "Or build it.", "## License", "BSD."}
+	b := []string{"# Title", "Intro.", "## License", "BSD.", "## Usage", "Run it.", "Or build it."}
+	dist, matA, _, moves := Distance(a, b, nil)
+	assert.Equal(t, "dist", dist, 2)
+	assert.StringEqual(t, "moves", moves, []Block{{5, 2, 2}})
+	assert.StringEqual(t, "matA", matA, []int{0, 1, 4, 5, 6, 2, 3})
+}
@@ -0,0 +1,339 @@
+/*
+msa package aligns multiple sequences with the pairwise edit-distance of the ed package.
+
+An Alignment is a matrix of indexes: Rows[s][c] is the index of the element of sequence s in column c, or -1 for a gap. The sum-of-pairs (SP) score of an alignment is the sum of costs of all pairs of rows in all columns, where a mismatch costs Options.Mismatch, an element against a gap costs Options.Gap, and matches and gap pairs cost nothing. Lower scores are better.
+
+CenterStar aligns every sequence to the one closest to all others, and merges the pairwise alignments. It is fast, and if 2*Gap >= Mismatch, its SP score is at most twice the optimal one.
+
+Progressive aligns profiles (alignments of subsets) following a guide tree built by UPGMA or neighbor joining from the distance matrix, and usually gives better scores.
+*/
+package msa
+
+import (
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Options defines the costs of alignments. A nil *Options means unit costs.
+type Options struct {
+	Mismatch, Gap int
+}
+
+func (opts *Options) costs() (mismatch, gap int) {
+	if opts == nil {
+		return 1, 1
+	}
+	return opts.Mismatch, opts.Gap
+}
+
+// Alignment is a gapped alignment of sequences.
+type Alignment struct {
+	// Rows[s][c] is the index of the element of sequence s in column c, or -1 for a gap
+	Rows [][]int
+	// Score is the sum-of-pairs score
+	Score int
+}
+
+// Chars splits strings into sequences of characters (runes).
+func Chars(strs ...string) [][]string {
+	seqs := make([][]string, len(strs))
+	for i, s := range strs {
+		for _, r := range s {
+			seqs[i] = append(seqs[i], string(r))
+		}
+	}
+	return seqs
+}
+
+// Gapped returns the alignment as a matrix of elements, where gaps are filled with gap.
+func (a *Alignment) Gapped(seqs [][]string, gap string) [][]string {
+	res := make([][]string, len(a.Rows))
+	for s, row := range a.Rows {
+		res[s] = make([]string, len(row))
+		for c, i := range row {
+			if i < 0 {
+				res[s][c] = gap
+			} else {
+				res[s][c] = seqs[s][i]
+			}
+		}
+	}
+	return res
+}
+
+// SumOfPairs returns the sum-of-pairs score of rows of an alignment of seqs.
+func SumOfPairs(seqs [][]string, rows [][]int, opts *Options) int {
+	mismatch, gap := opts.costs()
+	score := 0
+	for s := range rows {
+		for t := s + 1; t < len(rows); t++ {
+			for c, i := range rows[s] {
+				j := rows[t][c]
+				switch {
+				case i < 0 && j < 0:
+				case i < 0 || j < 0:
+					score += gap
+				case seqs[s][i] != seqs[t][j]:
+					score += mismatch
+				}
+			}
+		}
+	}
+	return score
+}
+
+// pairwise returns the edit-distance and the matching between two sequences.
+func pairwise(a, b []string, mismatch, gap int) (dist int, matA, matB []int) {
+	return ed.EditDistanceFFull(len(a), len(b), func(iA, iB int) int {
+		return ed.Ternary(a[iA] == b[iB], 0, mismatch)
+	}, ed.ConstCost(gap), ed.ConstCost(gap))
+}
+
+/*
+DistanceMatrix returns the pairwise edit-distances of sequences, with costs in opts.
+
+The time complexity is O(k^2 l^2) where k is the number of sequences and l is their length.
+*/
+func DistanceMatrix(seqs [][]string, opts *Options) [][]int {
+	mismatch, gap := opts.costs()
+	d := make([][]int, len(seqs))
+	for s := range d {
+		d[s] = make([]int, len(seqs))
+	}
+	for s := range seqs {
+		for t := s + 1; t < len(seqs); t++ {
+			d[s][t], _, _ = pairwise(seqs[s], seqs[t], mismatch, gap)
+			d[t][s] = d[s][t]
+		}
+	}
+	return d
+}
+
+/*
+CenterStar returns the center-star alignment of seqs: the center is the sequence with the minimum sum of distances to others, and every other sequence is aligned to it pairwise. Insertions relative to the center are left-justified in gap columns.
+
+The time complexity is O(k^2 l^2) where k is the number of sequences and l is their length.
+*/
+func CenterStar(seqs [][]string, opts *Options) *Alignment {
+	if len(seqs) == 0 {
+		return &Alignment{}
+	}
+	mismatch, gap := opts.costs()
+	dist := DistanceMatrix(seqs, opts)
+	center, best := 0, -1
+	for s, row := range dist {
+		sum := 0
+		for _, d := range row {
+			sum += d
+		}
+		if best < 0 || sum < best {
+			center, best = s, sum
+		}
+	}
+
+	lc := len(seqs[center])
+	// matches[s] is the matching of seqs[s] to the center, and ins[p] is the maximum number of elements inserted before center position p
+	matches := make([][]int, len(seqs))
+	ins := make([]int, lc+1)
+	for s := range seqs {
+		if s == center {
+			continue
+		}
+		_, _, matB := pairwise(seqs[center], seqs[s], mismatch, gap)
+		matches[s] = matB
+		p, n := 0, 0
+		for _, i := range matB {
+			if i < 0 {
+				n++
+				continue
+			}
+			if n > ins[p] {
+				ins[p] = n
+			}
+			p, n = i+1, 0
+		}
+		if n > ins[p] {
+			ins[p] = n
+		}
+	}
+
+	// start[p] is the column of the first insertion before center position p, and the center position is at start[p]+ins[p]
+	start := make([]int, lc+1)
+	cols := 0
+	for p := 0; p <= lc; p++ {
+		start[p] = cols
+		cols += ins[p] + 1
+	}
+	cols-- // no center column after the last position
+
+	rows := make([][]int, len(seqs))
+	for s := range rows {
+		row := make([]int, cols)
+		for c := range row {
+			row[c] = -1
+		}
+		if s == center {
+			for p := 0; p < lc; p++ {
+				row[start[p]+ins[p]] = p
+			}
+		} else {
+			p, n := 0, 0
+			for j, i := range matches[s] {
+				if i < 0 {
+					row[start[p]+n] = j
+					n++
+					continue
+				}
+				row[start[i]+ins[i]] = j
+				p, n = i+1, 0
+			}
+		}
+		rows[s] = row
+	}
+	return &Alignment{Rows: rows, Score: SumOfPairs(seqs, rows, opts)}
+}
+
+// profile is an alignment of a subset of sequences, where rows[k] is the row of sequence members[k].
+type profile struct {
+	members []int
+	rows    [][]int
+	// counts[c] is the number of each element in column c, and gaps[c] is the number of gaps
+	counts []map[string]int
+	gaps   []int
+}
+
+func (p *profile) cols() int {
+	return len(p.gaps)
+}
+
+func (p *profile) count(seqs [][]string) {
+	p.counts, p.gaps = make([]map[string]int, len(p.rows[0])), make([]int, len(p.rows[0]))
+	for c := range p.counts {
+		p.counts[c] = make(map[string]int)
+		for k, row := range p.rows {
+			if i := row[c]; i < 0 {
+				p.gaps[c]++
+			} else {
+				p.counts[c][seqs[p.members[k]][i]]++
+			}
+		}
+	}
+}
+
+// alignProfiles aligns two profiles by the DP of the ed package, where the costs are the sum-of-pairs costs between rows of p and rows of q.
+func alignProfiles(seqs [][]string, p, q *profile, mismatch, gap int) *profile {
+	np, nq := len(p.members), len(q.members)
+	change := func(iA, iB int) int {
+		rp, rq := np-p.gaps[iA], nq-q.gaps[iB]
+		same := 0
+		for e, n := range p.counts[iA] {
+			same += n * q.counts[iB][e]
+		}
+		return (rp*q.gaps[iB]+p.gaps[iA]*rq)*gap + (rp*rq-same)*mismatch
+	}
+	del := func(iA int) int {
+		return (np - p.gaps[iA]) * nq * gap
+	}
+	ins := func(iB int) int {
+		return (nq - q.gaps[iB]) * np * gap
+	}
+	_, matA, matB := ed.EditDistanceFFull(p.cols(), q.cols(), change, del, ins)
+
+	res := &profile{
+		members: append(append([]int(nil), p.members...), q.members...),
+		rows:    make([][]int, np+nq),
+	}
+	push := func(rows [][]int, off, c int) {
+		for k, row := range rows {
+			i := -1
+			if c >= 0 {
+				i = row[c]
+			}
+			res.rows[off+k] = append(res.rows[off+k], i)
+		}
+	}
+	for iA, iB := 0, 0; iA < p.cols() || iB < q.cols(); {
+		switch {
+		case iA < p.cols() && matA[iA] < 0:
+			push(p.rows, 0, iA)
+			push(q.rows, np, -1)
+			iA++
+		case iB < q.cols() && matB[iB] < 0:
+			push(p.rows, 0, -1)
+			push(q.rows, np, iB)
+			iB++
+		default:
+			push(p.rows, 0, iA)
+			push(q.rows, np, iB)
+			iA, iB = iA+1, iB+1
+		}
+	}
+	return res
+}
+
+/*
+Progressive returns the progressive alignment of seqs following the guide tree: the profiles of the two children of each internal node are aligned by the DP of the ed package with sum-of-pairs costs between columns, and gaps, once inserted, are kept. If tree is nil, UPGMA of the DistanceMatrix is used. The tree must contain every sequence exactly once.
+
+The time complexity is O(k^2 l^2) where k is the number of sequences and l is their length.
+*/
+func Progressive(seqs [][]string, tree *Tree, opts *Options) *Alignment {
+	if len(seqs) == 0 {
+		return &Alignment{}
+	}
+	mismatch, gap := opts.costs()
+	if tree == nil {
+		tree = UPGMA(DistanceMatrix(seqs, opts))
+	}
+
+	var align func(t *Tree) *profile
+	align = func(t *Tree) *profile {
+		if t.Left == nil && t.Right == nil {
+			row := make([]int, len(seqs[t.Leaf]))
+			for i := range row {
+				row[i] = i
+			}
+			p := &profile{members: []int{t.Leaf}, rows: [][]int{row}}
+			p.count(seqs)
+			return p
+		}
+		p := alignProfiles(seqs, align(t.Left), align(t.Right), mismatch, gap)
+		p.count(seqs)
+		return p
+	}
+	p := align(tree)
+
+	rows := make([][]int, len(seqs))
+	for k, s := range p.members {
+		rows[s] = p.rows[k]
+	}
+	return &Alignment{Rows: rows, Score: SumOfPairs(seqs, rows, opts)}
+}
+
+/*
+Consensus returns the consensus sequence by a majority vote in each column of the alignment. The most frequent element of a column is taken, where ties are broken in favor of the element reaching the count first in the order of rows, and a column is skipped if gaps are more frequent than any element.
+*/
+func (a *Alignment) Consensus(seqs [][]string) []string {
+	if len(a.Rows) == 0 {
+		return nil
+	}
+	var res []string
+	for c := range a.Rows[0] {
+		counts := make(map[string]int)
+		gaps, best, bestN := 0, "", 0
+		for s, row := range a.Rows {
+			i := row[c]
+			if i < 0 {
+				gaps++
+				continue
+			}
+			e := seqs[s][i]
+			counts[e]++
+			if counts[e] > bestN {
+				best, bestN = e, counts[e]
+			}
+		}
+		if bestN > 0 && bestN >= gaps {
+			res = append(res, best)
+		}
+	}
+	return res
+}
@@ -0,0 +1,136 @@
+package msa
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+// checkAlignment checks every row of a contains all elements of its sequence in order, and the score is correct.
+func checkAlignment(t *testing.T, name string, seqs [][]string, a *Alignment, opts *Options) {
+	assert.Equal(t, name+" rows", len(a.Rows), len(seqs))
+	for s, row := range a.Rows {
+		assert.Equal(t, fmt.Sprintf("%s len(Rows[%d])", name, s), len(row), len(a.Rows[0]))
+		next := 0
+		for _, i := range row {
+			if i >= 0 {
+				assert.Equal(t, fmt.Sprintf("%s Rows[%d]", name, s), i, next)
+				next++
+			}
+		}
+		assert.Equal(t, fmt.Sprintf("%s elements of Rows[%d]", name, s), next, len(seqs[s]))
+	}
+	for c := range a.Rows[0] {
+		gaps := 0
+		for _, row := range a.Rows {
+			if row[c] < 0 {
+				gaps++
+			}
+		}
+		assert.True(t, fmt.Sprintf("%s column %d is not all gaps", name, c), gaps < len(a.Rows))
+	}
+	assert.Equal(t, name+" Score", a.Score, SumOfPairs(seqs, a.Rows, opts))
+}
+
+func joinRows(m [][]string) string {
+	lines := make([]string, len(m))
+	for i, row := range m {
+		lines[i] = strings.Join(row, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestSumOfPairs(t *testing.T) {
+	seqs := Chars("ab", "b", "ac")
+	rows := [][]int{{0, 1}, {-1, 0}, {0, 1}}
+	// (a,-) (a,a) (-,a): 2 gaps; (b,b) (b,c) (b,c): 2 mismatches
+	assert.Equal(t, "unit", SumOfPairs(seqs, rows, nil), 4)
+	assert.Equal(t, "costs", SumOfPairs(seqs, rows, &Options{Mismatch: 3, Gap: 2}), 10)
+}
+
+func TestCenterStar(t *testing.T) {
+	test := func(strs []string, gapped string, score int) {
+		seqs := Chars(strs...)
+		a := CenterStar(seqs, nil)
+		name := fmt.Sprintf("CenterStar(%v)", strs)
+		checkAlignment(t, name, seqs, a, nil)
+		assert.Equal(t, name+" gapped", joinRows(a.Gapped(seqs, "-")), gapped)
+		assert.Equal(t, name+" Score", a.Score, score)
+	}
+
+	test([]string{"abc", "abc", "abc"}, "abc\nabc\nabc", 0)
+	test([]string{"abcd", "abd", "xabcd"}, "-abcd\n-ab-d\nxabcd", 4)
+	test([]string{"kitten", "sitting", "kitting"}, "kitten-\nsitting\nkitting", 6)
+	test([]string{"abc", "", "abc"}, "abc\n---\nabc", 6)
+	test([]string{"a"}, "a", 0)
+
+	assert.Equal(t, "empty", len(CenterStar(nil, nil).Rows), 0)
+}
+
+func TestProgressive(t *testing.T) {
+	test := func(strs []string, tree *Tree, gapped string, score int) {
+		seqs := Chars(strs...)
+		a := Progressive(seqs, tree, nil)
+		name := fmt.Sprintf("Progressive(%v)", strs)
+		checkAlignment(t, name, seqs, a, nil)
+		assert.Equal(t, name+" gapped", joinRows(a.Gapped(seqs, "-")), gapped)
+		assert.Equal(t, name+" Score", a.Score, score)
+	}
+
+	test([]string{"abc", "abc", "abc"}, nil, "abc\nabc\nabc", 0)
+	test([]string{"abcd", "abd", "xabcd"}, nil, "-abcd\n-ab-d\nxabcd", 4)
+	test([]string{"kitten", "sitting", "kitting"}, nil, "kitten-\nsitting\nkitting", 6)
+	test([]string{"abc", "", "abc"}, nil, "abc\n---\nabc", 6)
+	test([]string{"a"}, nil, "a", 0)
+
+	assert.Equal(t, "empty", len(Progressive(nil, nil, nil).Rows), 0)
+}
+
+func TestProgressive_Tokens(t *testing.T) {
+	logs := []string{
+		"user alice logged in from 10.0.0.1",
+		"user bob logged in from 10.0.0.2",
+		"user carol logged out",
+		"user dave logged in",
+	}
+	seqs := make([][]string, len(logs))
+	for i, l := range logs {
+		seqs[i] = strings.Fields(l)
+	}
+	for _, tree := range []*Tree{nil, NJ(DistanceMatrix(seqs, nil))} {
+		p := Progressive(seqs, tree, nil)
+		checkAlignment(t, "Progressive", seqs, p, nil)
+		c := CenterStar(seqs, nil)
+		checkAlignment(t, "CenterStar", seqs, c, nil)
+		assert.True(t, fmt.Sprintf("Progressive %d <= CenterStar %d", p.Score, c.Score), p.Score <= c.Score)
+	}
+}
+
+func ExampleProgressive() {
+	seqs := Chars("GATTACA", "GATCA", "GCTTACA", "GATTTACA")
+	a := Progressive(seqs, nil, nil)
+	for _, row := range a.Gapped(seqs, "-") {
+		fmt.Println(strings.Join(row, ""))
+	}
+	fmt.Println("score:", a.Score)
+	// Output:
+	// GATT-ACA
+	// GAT---CA
+	// GCTT-ACA
+	// GATTTACA
+	// score: 12
+}
+
+func TestAlignment_Consensus(t *testing.T) {
+	seqs := Chars("GATTACA", "GATCA", "GCTTACA", "GATTTACA")
+	a := Progressive(seqs, nil, nil)
+	assert.Equal(t, "Consensus", strings.Join(a.Consensus(seqs), ""), "GATTACA")
+
+	// ties of elements and gaps
+	seqs = Chars("ab", "b", "cb", "a")
+	a = &Alignment{Rows: [][]int{{0, 1}, {-1, 0}, {0, 1}, {0, -1}}}
+	assert.Equal(t, "ties", strings.Join(a.Consensus(seqs), ""), "ab")
+	assert.Equal(t, "empty", len((&Alignment{}).Consensus(nil)), 0)
+}
@@ -0,0 +1,138 @@
+package msa
+
+// Tree is a binary guide tree of sequences. A leaf has nil children and the index of its sequence in Leaf. Leaf is -1 for internal nodes.
+type Tree struct {
+	Left, Right *Tree
+	Leaf        int
+}
+
+// Leaves returns the indexes of sequences under the tree, from left to right.
+func (t *Tree) Leaves() []int {
+	if t == nil {
+		return nil
+	}
+	if t.Left == nil && t.Right == nil {
+		return []int{t.Leaf}
+	}
+	return append(t.Left.Leaves(), t.Right.Leaves()...)
+}
+
+func leaves(n int) []*Tree {
+	nodes := make([]*Tree, n)
+	for i := range nodes {
+		nodes[i] = &Tree{Leaf: i}
+	}
+	return nodes
+}
+
+func floatMatrix(dist [][]int) [][]float64 {
+	d := make([][]float64, len(dist))
+	for i, row := range dist {
+		d[i] = make([]float64, len(row))
+		for j, v := range row {
+			d[i][j] = float64(v)
+		}
+	}
+	return d
+}
+
+/*
+UPGMA returns the guide tree built by the unweighted pair group method with arithmetic mean: the two closest clusters are joined repeatedly, and the distance between clusters is the average distance between their sequences. Ties are broken by the lowest indexes. It returns nil if dist is empty.
+
+The time complexity is O(k^3) where k is the number of sequences.
+*/
+func UPGMA(dist [][]int) *Tree {
+	n := len(dist)
+	if n == 0 {
+		return nil
+	}
+	d, nodes := floatMatrix(dist), leaves(n)
+	sizes := make([]int, n)
+	for i := range sizes {
+		sizes[i] = 1
+	}
+	// nodes[i] == nil if cluster i has been joined into another
+	for left := n; left > 1; left-- {
+		bi, bj := -1, -1
+		for i := 0; i < n; i++ {
+			if nodes[i] == nil {
+				continue
+			}
+			for j := i + 1; j < n; j++ {
+				if nodes[j] != nil && (bi < 0 || d[i][j] < d[bi][bj]) {
+					bi, bj = i, j
+				}
+			}
+		}
+		for k := 0; k < n; k++ {
+			if nodes[k] != nil && k != bi && k != bj {
+				v := (d[bi][k]*float64(sizes[bi]) + d[bj][k]*float64(sizes[bj])) / float64(sizes[bi]+sizes[bj])
+				d[bi][k], d[k][bi] = v, v
+			}
+		}
+		nodes[bi] = &Tree{Left: nodes[bi], Right: nodes[bj], Leaf: -1}
+		sizes[bi] += sizes[bj]
+		nodes[bj] = nil
+	}
+	return nodes[0]
+}
+
+/*
+NJ returns the guide tree built by neighbor joining, rooted at the last join. Unlike UPGMA, neighbor joining does not assume a constant rate of changes. Ties are broken by the lowest indexes. It returns nil if dist is empty.
+
+The time complexity is O(k^3) where k is the number of sequences.
+*/
+func NJ(dist [][]int) *Tree {
+	n := len(dist)
+	if n == 0 {
+		return nil
+	}
+	d, nodes := floatMatrix(dist), leaves(n)
+	// nodes[i] == nil if cluster i has been joined into another
+	for left := n; left > 2; left-- {
+		r := make([]float64, n)
+		for i := 0; i < n; i++ {
+			if nodes[i] == nil {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if nodes[j] != nil {
+					r[i] += d[i][j]
+				}
+			}
+		}
+		bi, bj, best := -1, -1, 0.
+		for i := 0; i < n; i++ {
+			if nodes[i] == nil {
+				continue
+			}
+			for j := i + 1; j < n; j++ {
+				if nodes[j] == nil {
+					continue
+				}
+				if q := float64(left-2)*d[i][j] - r[i] - r[j]; bi < 0 || q < best {
+					bi, bj, best = i, j, q
+				}
+			}
+		}
+		for k := 0; k < n; k++ {
+			if nodes[k] != nil && k != bi && k != bj {
+				v := (d[bi][k] + d[bj][k] - d[bi][bj]) / 2
+				d[bi][k], d[k][bi] = v, v
+			}
+		}
+		nodes[bi] = &Tree{Left: nodes[bi], Right: nodes[bj], Leaf: -1}
+		nodes[bj] = nil
+	}
+
+	var rest []*Tree
+	for _, t := range nodes {
+		if t != nil {
+			rest = append(rest, t)
+		}
+	}
+	if len(rest) == 1 {
+		return rest[0]
+	}
+	return &Tree{Left: rest[0], Right: rest[1], Leaf: -1}
+}
@@ -0,0 +1,41 @@
+package msa
+
+import (
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestUPGMA(t *testing.T) {
+	dist := [][]int{
+		{0, 2, 8, 8},
+		{2, 0, 8, 8},
+		{8, 8, 0, 4},
+		{8, 8, 4, 0},
+	}
+	tree := UPGMA(dist)
+	assert.StringEqual(t, "Leaves", tree.Leaves(), []int{0, 1, 2, 3})
+	assert.StringEqual(t, "Left", tree.Left.Leaves(), []int{0, 1})
+	assert.StringEqual(t, "Right", tree.Right.Leaves(), []int{2, 3})
+
+	assert.StringEqual(t, "single", UPGMA([][]int{{0}}).Leaves(), []int{0})
+	assert.True(t, "empty", UPGMA(nil) == nil)
+}
+
+func TestNJ(t *testing.T) {
+	// the additive tree ((0:5, 1:2):3, 2:4, 3:7) of the classic example
+	dist := [][]int{
+		{0, 7, 12, 15},
+		{7, 0, 9, 12},
+		{12, 9, 0, 11},
+		{15, 12, 11, 0},
+	}
+	tree := NJ(dist)
+	assert.StringEqual(t, "Leaves", tree.Leaves(), []int{0, 1, 2, 3})
+	// 0 and 1 are joined first
+	assert.StringEqual(t, "Left.Left", tree.Left.Left.Leaves(), []int{0, 1})
+
+	assert.StringEqual(t, "pair", NJ([][]int{{0, 1}, {1, 0}}).Leaves(), []int{0, 1})
+	assert.StringEqual(t, "single", NJ([][]int{{0}}).Leaves(), []int{0})
+	assert.True(t, "empty", NJ(nil) == nil)
+}
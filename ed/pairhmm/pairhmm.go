@@ -0,0 +1,302 @@
+/*
+pairhmm package computes probabilistic alignments of two lists with a pair hidden Markov model, as described in "Biological Sequence Analysis" by Durbin et al.
+
+The model has three states: M emits an aligned pair (a[iA], b[iB]), X emits a[iA] against a gap, and Y emits b[iB] against a gap. Transitions are controlled by Params:
+
+	M -> X, M -> Y: Delta (gap open)
+	X -> X, Y -> Y: Epsilon (gap extend)
+	any -> end:     Tau
+	M -> M:         1 - 2*Delta - Tau
+	X -> M, Y -> M: 1 - Epsilon - Tau
+
+Instead of a single optimal alignment, Forward returns the total probability of all alignments, Posteriors the probability that each pair is aligned, and MEA the alignment maximizing the expected number of correctly aligned pairs. All computations are in log-space, so long lists do not underflow.
+*/
+package pairhmm
+
+import (
+	"errors"
+	"math"
+)
+
+// Interface defines a pair of lists and log emission probabilities.
+type Interface interface {
+	// LenA returns the length of list A
+	LenA() int
+
+	// LenB returns the length of list B
+	LenB() int
+
+	// LogMatch returns the log probability of emitting the pair (a[iA], b[iB]) in state M
+	LogMatch(iA, iB int) float64
+
+	// LogGapA returns the log probability of emitting a[iA] in state X
+	LogGapA(iA int) float64
+
+	// LogGapB returns the log probability of emitting b[iB] in state Y
+	LogGapB(iB int) float64
+}
+
+// Params is the transition probabilities. Delta, Epsilon and Tau must be positive, and 2*Delta+Tau and Epsilon+Tau less than 1.
+type Params struct {
+	Delta, Epsilon, Tau float64
+}
+
+// ErrInvalidParams is returned if Params are invalid.
+var ErrInvalidParams = errors.New("pairhmm: invalid transition probabilities")
+
+// ErrInvalidEmission is returned if a log emission probability of an Interface is NaN or positive, i.e. the probability is out of [0, 1].
+var ErrInvalidEmission = errors.New("pairhmm: invalid emission probability")
+
+// Validate returns ErrInvalidParams if p is invalid.
+func (p Params) Validate() error {
+	if !(p.Delta > 0 && p.Epsilon > 0 && p.Tau > 0 && 2*p.Delta+p.Tau < 1 && p.Epsilon+p.Tau < 1) {
+		return ErrInvalidParams
+	}
+	return nil
+}
+
+// DefaultParams is a set of parameters for lists of moderate similarity.
+var DefaultParams = Params{Delta: 0.05, Epsilon: 0.3, Tau: 0.01}
+
+type runes struct {
+	a, b          []rune
+	logEq, logNeq float64
+	logGap        float64
+}
+
+// Interface.LenA
+func (in *runes) LenA() int {
+	return len(in.a)
+}
+
+// Interface.LenB
+func (in *runes) LenB() int {
+	return len(in.b)
+}
+
+// Interface.LogMatch
+func (in *runes) LogMatch(iA, iB int) float64 {
+	if in.a[iA] == in.b[iB] {
+		return in.logEq
+	}
+	return in.logNeq
+}
+
+// Interface.LogGapA
+func (in *runes) LogGapA(iA int) float64 {
+	return in.logGap
+}
+
+// Interface.LogGapB
+func (in *runes) LogGapB(iB int) float64 {
+	return in.logGap
+}
+
+/*
+Runes returns an Interface of runes of strings a and b over an alphabet of the given size, which must be at least 2, and pEq must be in [0, 1]. In state M, a pair of equal runes is emitted with probability pEq/size, and a pair of different runes (1-pEq)/(size*(size-1)). Gaps emit every rune with probability 1/size.
+*/
+func Runes(a, b string, pEq float64, size int) Interface {
+	n := float64(size)
+	return &runes{
+		a:      []rune(a),
+		b:      []rune(b),
+		logEq:  math.Log(pEq / n),
+		logNeq: math.Log((1 - pEq) / (n * (n - 1))),
+		logGap: -math.Log(n),
+	}
+}
+
+// logSum returns log(exp(x) + exp(y)).
+func logSum(x, y float64) float64 {
+	if x < y {
+		x, y = y, x
+	}
+	if math.IsInf(y, -1) {
+		return x
+	}
+	return x + math.Log1p(math.Exp(y-x))
+}
+
+// logs of transition probabilities
+type trans struct {
+	mm, gapOpen, gapExt, gm, end float64
+}
+
+func (p Params) logs() trans {
+	return trans{
+		mm:      math.Log(1 - 2*p.Delta - p.Tau),
+		gapOpen: math.Log(p.Delta),
+		gapExt:  math.Log(p.Epsilon),
+		gm:      math.Log(1 - p.Epsilon - p.Tau),
+		end:     math.Log(p.Tau),
+	}
+}
+
+// newMatrix returns a (la+1)x(lb+1) matrix filled with -Inf.
+func newMatrix(la, lb int) [][]float64 {
+	f := make([][]float64, la+1)
+	for i := range f {
+		f[i] = make([]float64, lb+1)
+		for j := range f[i] {
+			f[i][j] = math.Inf(-1)
+		}
+	}
+	return f
+}
+
+// forward returns the log forward matrices, where m[i][j] is the log probability of emitting a[:i] and b[:j] and ending in state M. ErrInvalidEmission is returned if an emission probability is invalid.
+func forward(in Interface, t trans) (m, x, y [][]float64, err error) {
+	la, lb := in.LenA(), in.LenB()
+	m, x, y = newMatrix(la, lb), newMatrix(la, lb), newMatrix(la, lb)
+	m[0][0] = 0
+	for i := 0; i <= la; i++ {
+		for j := 0; j <= lb; j++ {
+			if i > 0 && j > 0 {
+				e := in.LogMatch(i-1, j-1)
+				if !(e <= 0) {
+					return nil, nil, nil, ErrInvalidEmission
+				}
+				m[i][j] = e + logSum(t.mm+m[i-1][j-1], t.gm+logSum(x[i-1][j-1], y[i-1][j-1]))
+			}
+			if i > 0 {
+				e := in.LogGapA(i - 1)
+				if !(e <= 0) {
+					return nil, nil, nil, ErrInvalidEmission
+				}
+				x[i][j] = e + logSum(t.gapOpen+m[i-1][j], t.gapExt+x[i-1][j])
+			}
+			if j > 0 {
+				e := in.LogGapB(j - 1)
+				if !(e <= 0) {
+					return nil, nil, nil, ErrInvalidEmission
+				}
+				y[i][j] = e + logSum(t.gapOpen+m[i][j-1], t.gapExt+y[i][j-1])
+			}
+		}
+	}
+	return m, x, y, nil
+}
+
+// backward returns the log backward matrices, where m[i][j] is the log probability of emitting a[i:] and b[j:] and ending, from state M.
+func backward(in Interface, t trans) (m, x, y [][]float64) {
+	la, lb := in.LenA(), in.LenB()
+	m, x, y = newMatrix(la, lb), newMatrix(la, lb), newMatrix(la, lb)
+	for i := la; i >= 0; i-- {
+		for j := lb; j >= 0; j-- {
+			if i == la && j == lb {
+				m[i][j], x[i][j], y[i][j] = t.end, t.end, t.end
+				continue
+			}
+			toM, toX, toY := math.Inf(-1), math.Inf(-1), math.Inf(-1)
+			if i < la && j < lb {
+				toM = in.LogMatch(i, j) + m[i+1][j+1]
+			}
+			if i < la {
+				toX = in.LogGapA(i) + x[i+1][j]
+			}
+			if j < lb {
+				toY = in.LogGapB(j) + y[i][j+1]
+			}
+			m[i][j] = logSum(t.mm+toM, t.gapOpen+logSum(toX, toY))
+			x[i][j] = logSum(t.gm+toM, t.gapExt+toX)
+			y[i][j] = logSum(t.gm+toM, t.gapExt+toY)
+		}
+	}
+	return m, x, y
+}
+
+/*
+Forward returns the log of the total probability of all alignments of the two lists. ErrInvalidParams is returned if p is invalid, and ErrInvalidEmission if an emission probability of in is invalid.
+
+The time and space complexities are O(mn) where m and n are lengths of the two lists.
+*/
+func Forward(in Interface, p Params) (float64, error) {
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+	t := p.logs()
+	m, x, y, err := forward(in, t)
+	if err != nil {
+		return 0, err
+	}
+	la, lb := in.LenA(), in.LenB()
+	return t.end + logSum(m[la][lb], logSum(x[la][lb], y[la][lb])), nil
+}
+
+/*
+Posteriors returns the log of the total probability, and post where post[iA][iB] is the posterior probability that a[iA] is aligned to b[iB], computed by the forward-backward algorithm. For each iA, the sum of post[iA] is at most 1. Errors are returned as Forward does.
+
+The time and space complexities are O(mn) where m and n are lengths of the two lists.
+*/
+func Posteriors(in Interface, p Params) (logProb float64, post [][]float64, err error) {
+	if err := p.Validate(); err != nil {
+		return 0, nil, err
+	}
+	t := p.logs()
+	fm, fx, fy, err := forward(in, t)
+	if err != nil {
+		return 0, nil, err
+	}
+	bm, _, _ := backward(in, t)
+	la, lb := in.LenA(), in.LenB()
+	logProb = t.end + logSum(fm[la][lb], logSum(fx[la][lb], fy[la][lb]))
+
+	post = make([][]float64, la)
+	for i := range post {
+		post[i] = make([]float64, lb)
+		for j := range post[i] {
+			post[i][j] = math.Exp(fm[i+1][j+1] + bm[i+1][j+1] - logProb)
+		}
+	}
+	return logProb, post, nil
+}
+
+/*
+MEA returns the maximum expected accuracy alignment, i.e. the matching maximizing the sum of posteriors of aligned pairs, and the sum as the expected number of correctly aligned pairs. Results are in the same format as ed.EditDistanceFull: matA[iA] is the index in B aligned to a[iA], or -1 if a[iA] is deleted, and similarly for matB. Errors are returned as Forward does.
+
+The time and space complexities are O(mn) where m and n are lengths of the two lists.
+*/
+func MEA(in Interface, p Params) (accuracy float64, matA, matB []int, err error) {
+	_, post, err := Posteriors(in, p)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	la, lb := in.LenA(), in.LenB()
+
+	// f[i][j] is the maximum accuracy of aligning a[:i] and b[:j]
+	f := make([][]float64, la+1)
+	for i := range f {
+		f[i] = make([]float64, lb+1)
+		for j := range f[i] {
+			switch {
+			case i > 0 && j > 0:
+				f[i][j] = math.Max(f[i-1][j-1]+post[i-1][j-1], math.Max(f[i-1][j], f[i][j-1]))
+			case i > 0:
+				f[i][j] = f[i-1][j]
+			case j > 0:
+				f[i][j] = f[i][j-1]
+			}
+		}
+	}
+
+	matA, matB = make([]int, la), make([]int, lb)
+	for i := range matA {
+		matA[i] = -1
+	}
+	for j := range matB {
+		matB[j] = -1
+	}
+	for i, j := la, lb; i > 0 && j > 0; {
+		switch {
+		case f[i][j] == f[i-1][j]:
+			i--
+		case f[i][j] == f[i][j-1]:
+			j--
+		default:
+			matA[i-1], matB[j-1] = j-1, i-1
+			i--
+			j--
+		}
+	}
+	return f[la][lb], matA, matB, nil
+}
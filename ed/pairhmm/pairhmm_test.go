@@ -0,0 +1,132 @@
+package pairhmm
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+// bruteForce returns the total probability by enumerating all state paths.
+func bruteForce(in Interface, p Params) float64 {
+	la, lb := in.LenA(), in.LenB()
+	// state: 0 for M (and begin), 1 for X, 2 for Y
+	var rec func(i, j, state int) float64
+	rec = func(i, j, state int) float64 {
+		if i == la && j == lb {
+			return p.Tau
+		}
+		toM, toGap := 1-2*p.Delta-p.Tau, p.Delta
+		if state != 0 {
+			toM, toGap = 1-p.Epsilon-p.Tau, 0
+		}
+		total := 0.0
+		if i < la && j < lb {
+			total += toM * math.Exp(in.LogMatch(i, j)) * rec(i+1, j+1, 0)
+		}
+		if i < la && state != 2 {
+			tr := toGap
+			if state == 1 {
+				tr = p.Epsilon
+			}
+			total += tr * math.Exp(in.LogGapA(i)) * rec(i+1, j, 1)
+		}
+		if j < lb && state != 1 {
+			tr := toGap
+			if state == 2 {
+				tr = p.Epsilon
+			}
+			total += tr * math.Exp(in.LogGapB(j)) * rec(i, j+1, 2)
+		}
+		return total
+	}
+	return rec(0, 0, 0)
+}
+
+func TestForward(t *testing.T) {
+	test := func(a, b string) {
+		in := Runes(a, b, 0.9, 4)
+		exp := bruteForce(in, DefaultParams)
+		logProb, err := Forward(in, DefaultParams)
+		assert.NoErrorOrDie(t, err)
+		act := math.Exp(logProb)
+		assert.True(t, fmt.Sprintf("Forward(%s, %s) = %v, expected %v", a, b, act, exp), math.Abs(act-exp) <= 1e-12*exp)
+
+		// the backward algorithm gives the same probability
+		bm, _, _ := backward(in, DefaultParams.logs())
+		assert.True(t, fmt.Sprintf("backward(%s, %s)", a, b), math.Abs(bm[0][0]-logProb) < 1e-9)
+	}
+
+	test("", "")
+	test("a", "")
+	test("", "b")
+	test("ab", "ab")
+	test("abc", "acd")
+	test("aabb", "ba")
+}
+
+func TestPosteriors(t *testing.T) {
+	in := Runes("abcabc", "abcxabc", 0.9, 4)
+	_, post, err := Posteriors(in, DefaultParams)
+	assert.NoErrorOrDie(t, err)
+	for i, row := range post {
+		sum := 0.0
+		for _, p := range row {
+			assert.True(t, "posterior in [0, 1]", p >= 0 && p <= 1+1e-9)
+			sum += p
+		}
+		assert.True(t, fmt.Sprintf("sum of post[%d] = %v <= 1", i, sum), sum <= 1+1e-9)
+	}
+	assert.True(t, "post[0][0] is confident", post[0][0] > 0.9)
+	assert.True(t, "post[5][6] is confident", post[5][6] > 0.9)
+}
+
+func TestMEA(t *testing.T) {
+	test := func(a, b string, expA, expB []int) {
+		_, matA, matB, err := MEA(Runes(a, b, 0.9, 4), DefaultParams)
+		assert.NoErrorOrDie(t, err)
+		assert.StringEqual(t, fmt.Sprintf("MEA(%s, %s) matA", a, b), matA, expA)
+		assert.StringEqual(t, fmt.Sprintf("MEA(%s, %s) matB", a, b), matB, expB)
+	}
+
+	test("abc", "abc", []int{0, 1, 2}, []int{0, 1, 2})
+	test("abcabc", "abcxabc", []int{0, 1, 2, 4, 5, 6}, []int{0, 1, 2, -1, 3, 4, 5})
+	test("", "ab", []int{}, []int{-1, -1})
+}
+
+func TestLong(t *testing.T) {
+	a := strings.Repeat("abcd", 250)
+	b := strings.Repeat("abdc", 250)
+	in := Runes(a, b, 0.9, 4)
+	logProb, err := Forward(in, DefaultParams)
+	assert.NoErrorOrDie(t, err)
+	assert.True(t, fmt.Sprintf("log probability %v is finite", logProb), !math.IsInf(logProb, 0) && !math.IsNaN(logProb))
+	acc, _, _, err := MEA(in, DefaultParams)
+	assert.NoErrorOrDie(t, err)
+	assert.True(t, fmt.Sprintf("accuracy %v", acc), acc > 0 && acc <= 1000)
+}
+
+func TestInvalid(t *testing.T) {
+	test := func(name string, in Interface, p Params, exp error) {
+		_, err := Forward(in, p)
+		assert.Equal(t, name+" Forward", err, exp)
+		_, _, err = Posteriors(in, p)
+		assert.Equal(t, name+" Posteriors", err, exp)
+		_, _, _, err = MEA(in, p)
+		assert.Equal(t, name+" MEA", err, exp)
+	}
+
+	in := Runes("abc", "abd", 0.9, 4)
+	test("negative Delta", in, Params{Delta: -0.1, Epsilon: 0.3, Tau: 0.01}, ErrInvalidParams)
+	test("zero Tau", in, Params{Delta: 0.05, Epsilon: 0.3}, ErrInvalidParams)
+	test("2*Delta+Tau > 1", in, Params{Delta: 0.5, Epsilon: 0.3, Tau: 0.01}, ErrInvalidParams)
+	test("Epsilon+Tau > 1", in, Params{Delta: 0.05, Epsilon: 1, Tau: 0.01}, ErrInvalidParams)
+	test("NaN", in, Params{Delta: math.NaN(), Epsilon: 0.3, Tau: 0.01}, ErrInvalidParams)
+	assert.NoError(t, DefaultParams.Validate())
+
+	test("pEq > 1", Runes("abc", "abc", 1.5, 4), DefaultParams, ErrInvalidEmission)
+	test("size 1", Runes("abc", "abd", 0.9, 1), DefaultParams, ErrInvalidEmission)
+	test("gap", Runes("a", "", 0.9, 0), DefaultParams, ErrInvalidEmission)
+}
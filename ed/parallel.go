@@ -0,0 +1,306 @@
+package ed
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// tileSize is the number of rows and columns of a tile in the parallel DP.
+var tileSize = 256
+
+// directCells is the maximum number of cells solved directly in EditDistanceFullParallel, instead of splitting further.
+var directCells = 1 << 16
+
+// costFuncs are cost functions with index offsets, used for sub-problems.
+type costFuncs struct {
+	change func(iA, iB int) int
+	del    func(iA int) int
+	ins    func(iB int) int
+}
+
+// sub returns the cost functions of a[i0:] and b[j0:].
+func (c costFuncs) sub(i0, j0 int) costFuncs {
+	return costFuncs{
+		change: func(iA, iB int) int { return c.change(i0+iA, j0+iB) },
+		del:    func(iA int) int { return c.del(i0 + iA) },
+		ins:    func(iB int) int { return c.ins(j0 + iB) },
+	}
+}
+
+func workerCount(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// bands splits [0, n) into bands of tileSize, and returns the boundaries.
+func bands(n int) []int {
+	bs := []int{0}
+	for b := tileSize; b < n; b += tileSize {
+		bs = append(bs, b)
+	}
+	return append(bs, n)
+}
+
+/*
+sweep computes the DP table of a sub-problem from its top row f[0][0..lb] and left column f[0..la][0], where la = len(left)-1 and lb = len(top)-1, by tiles on anti-diagonals in parallel. Tiles on an anti-diagonal depend only on tiles on the previous one, and each reads the bottom row of the tile above it and the right column of the tile to its left. Ties are broken in the same order as EditDistanceFull.
+
+It returns the bottom row and the right column of the table. If origins is true, orig[j] is the column where the matching backtracked from f[la][j] first reaches the top row.
+*/
+func sweep(top, left []int, c costFuncs, workers int, origins bool) (bottom, right, orig []int) {
+	rows, cols := bands(len(left)-1), bands(len(top)-1)
+	nr, nc := len(rows)-1, len(cols)-1
+
+	// hs[tj] is the bottom row of the last computed tile in column tj, for columns cols[tj]..cols[tj+1], and hos[tj] is its origins
+	hs, hos := make([][]int, nc), make([][]int, nc)
+	for tj := range hs {
+		c0, c1 := cols[tj], cols[tj+1]
+		hs[tj] = append([]int(nil), top[c0:c1+1]...)
+		if origins {
+			hos[tj] = make([]int, c1-c0+1)
+			for j := range hos[tj] {
+				hos[tj][j] = c0 + j
+			}
+		}
+	}
+	// vs[ti] is the right column of the last computed tile in row ti, for rows rows[ti]..rows[ti+1], and vos[ti] is its origins
+	vs, vos := make([][]int, nr), make([][]int, nr)
+	for ti := range vs {
+		r0, r1 := rows[ti], rows[ti+1]
+		vs[ti] = append([]int(nil), left[r0:r1+1]...)
+		if origins {
+			vos[ti] = make([]int, r1-r0+1)
+		}
+	}
+
+	tile := func(ti, tj int) {
+		r0, r1, c0, c1 := rows[ti], rows[ti+1], cols[tj], cols[tj+1]
+		f, v := hs[tj], vs[ti] // f is updated in place from the top row to the bottom row
+		o, vo := hos[tj], vos[ti]
+		right := make([]int, r1-r0+1)
+		right[0] = f[len(f)-1]
+		var rightO []int
+		if origins {
+			rightO = make([]int, r1-r0+1)
+			rightO[0] = o[len(o)-1]
+		}
+		for i := r0 + 1; i <= r1; i++ {
+			fj1, oj1 := f[0], 0 // fj1 and oj1 are the values of f[j - 1] and o[j - 1] in last iteration
+			f[0] = v[i-r0]
+			if origins {
+				oj1, o[0] = o[0], vo[i-r0]
+			}
+			for j := c0 + 1; j <= c1; j++ {
+				mn, op := addCost(f[j-c0], c.del(i-1)), opDEL // delete
+				if x := addCost(f[j-c0-1], c.ins(j-1)); x < mn {
+					// insert
+					mn, op = x, opINS
+				}
+				if x := addCost(fj1, c.change(i-1, j-1)); x < mn {
+					// change/matched
+					mn, op = x, opCHANGE
+				}
+				fj1, f[j-c0] = f[j-c0], mn
+
+				if origins {
+					org := o[j-c0]
+					switch op {
+					case opINS:
+						org = o[j-c0-1]
+					case opCHANGE:
+						org = oj1
+					}
+					oj1, o[j-c0] = o[j-c0], org
+				}
+			}
+			right[i-r0] = f[len(f)-1]
+			if origins {
+				rightO[i-r0] = o[len(o)-1]
+			}
+		}
+		vs[ti], vos[ti] = right, rightO
+	}
+
+	workers = workerCount(workers)
+	for d := 0; d < nr+nc-1; d++ {
+		lo, hi := d-nc+1, d
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > nr-1 {
+			hi = nr - 1
+		}
+		n := hi - lo + 1
+		if n == 1 || workers == 1 {
+			for ti := lo; ti <= hi; ti++ {
+				tile(ti, d-ti)
+			}
+			continue
+		}
+
+		var wg sync.WaitGroup
+		next := int64(lo)
+		for w := 0; w < workers && w < n; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					ti := int(atomic.AddInt64(&next, 1)) - 1
+					if ti > hi {
+						return
+					}
+					tile(ti, d-ti)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	join := func(parts [][]int) []int {
+		var res []int
+		for k, part := range parts {
+			if k > 0 {
+				part = part[1:]
+			}
+			res = append(res, part...)
+		}
+		return res
+	}
+	bottom, right = join(hs), join(vs)
+	if origins {
+		orig = join(hos)
+	}
+	return bottom, right, orig
+}
+
+// borders returns the top row and the left column of the DP table.
+func borders(la, lb int, c costFuncs) (top, left []int) {
+	top, left = make([]int, lb+1), make([]int, la+1)
+	for j := 1; j <= lb; j++ {
+		top[j] = addCost(top[j-1], c.ins(j-1))
+	}
+	for i := 1; i <= la; i++ {
+		left[i] = addCost(left[i-1], c.del(i-1))
+	}
+	return top, left
+}
+
+// lastRow returns f[la][0..lb] of the DP table, computed by sweep.
+func lastRow(la, lb int, c costFuncs, workers int) []int {
+	top, left := borders(la, lb, c)
+	bottom, _, _ := sweep(top, left, c, workers, false)
+	return bottom
+}
+
+/*
+direct computes the DP table of a[i0:i1] and b[j0:j1] from its top row and left column as sweep does, and sets matA and matB by the matching backtracked from (i1, j1) to (i0, j0) as EditDistanceFull does.
+*/
+func direct(i0, i1, j0, j1 int, top, left []int, c costFuncs, matA, matB []int) {
+	la, lb := i1-i0, j1-j0
+	f := append([]int(nil), top...)
+	ops := make([]byte, la*lb)
+	p := 0
+	for i := 1; i <= la; i++ {
+		fj1 := f[0] // fj1 is the value of f[j - 1] in last iteration
+		f[0] = left[i]
+		for j := 1; j <= lb; j++ {
+			mn, op := addCost(f[j], c.del(i0+i-1)), opDEL // delete
+			if v := addCost(f[j-1], c.ins(j0+j-1)); v < mn {
+				// insert
+				mn, op = v, opINS
+			}
+			if v := addCost(fj1, c.change(i0+i-1, j0+j-1)); v < mn {
+				// change/matched
+				mn, op = v, opCHANGE
+			}
+			fj1, f[j], ops[p] = f[j], mn, op
+			p++
+		}
+	}
+
+	subA, subB := matchingFromOps(la, lb, ops)
+	for i, j := range subA {
+		if j >= 0 {
+			j += j0
+		}
+		matA[i0+i] = j
+	}
+	for j, i := range subB {
+		if i >= 0 {
+			i += i0
+		}
+		matB[j0+j] = i
+	}
+}
+
+/*
+EditDistanceParallel returns the same edit-distance as EditDistance, computed by the DP table split into tiles, where tiles on each anti-diagonal are processed concurrently by at most workers goroutines. If workers <= 0, runtime.GOMAXPROCS(0) is used. The cost functions of in are called concurrently.
+
+The time complexity is O(mn/p) where m and n are lengths of a and b, and p is the number of workers, and space complexity is O(m+n).
+*/
+func EditDistanceParallel(in Interface, workers int) int {
+	if mid, _, _ := trim(in); mid != nil {
+		in = mid
+	}
+	la, lb := in.LenA(), in.LenB()
+	c := costFuncs{in.CostOfChange, in.CostOfDel, in.CostOfIns}
+	return lastRow(la, lb, c, workers)[lb]
+}
+
+/*
+EditDistanceFullParallel returns the same edit-distance and matching as EditDistanceFull without the O(mn) table of operations, by divide-and-conquer, where the DP of each division is computed as EditDistanceParallel does. The cost functions of in are called concurrently.
+
+Each division computes the middle row of the DP table, and then the column where the matching of EditDistanceFull, backtracked from the end, reaches the middle row, so ties are broken in the same order as EditDistanceFull. The results are deterministic and the same for any number of workers.
+
+The time complexity is O(mn/p) where m and n are lengths of a and b, and p is the number of workers, and space complexity is O(m + n log m).
+*/
+func EditDistanceFullParallel(in Interface, workers int) (dist int, matA, matB []int) {
+	if mid, pre, suf := trim(in); mid != nil {
+		dist, midA, midB := EditDistanceFullParallel(mid, workers)
+		matA, matB = splice(in.LenA(), in.LenB(), pre, suf, midA, midB)
+		return dist, matA, matB
+	}
+	la, lb := in.LenA(), in.LenB()
+	c := costFuncs{in.CostOfChange, in.CostOfDel, in.CostOfIns}
+	matA, matB = make([]int, la), make([]int, lb)
+
+	// split sets the matching of a[i0:i1] and b[j0:j1], which the matching passes at both (i0, j0) and (i1, j1), given the top row and left column of the DP table
+	var split func(i0, i1, j0, j1 int, top, left []int)
+	split = func(i0, i1, j0, j1 int, top, left []int) {
+		if i1-i0 <= 1 || (i1-i0)*(j1-j0) <= directCells {
+			direct(i0, i1, j0, j1, top, left, c, matA, matB)
+			return
+		}
+
+		mid := (i0 + i1) / 2
+		row, _, _ := sweep(top, left[:mid-i0+1], c.sub(i0, j0), workers, false)
+		lower := c.sub(mid, j0)
+		_, _, orig := sweep(row, left[mid-i0:], lower, workers, true)
+		// the matching first reaches row mid at (mid, j0+k)
+		k := orig[j1-j0]
+		// the left column of the lower part
+		_, col, _ := sweep(row[:k+1], left[mid-i0:], lower, workers, false)
+
+		split(mid, i1, j0+k, j1, row[k:], col)
+		split(i0, mid, j0, j0+k, top[:k+1], left[:mid-i0+1])
+	}
+	top, left := borders(la, lb, c)
+	split(0, la, 0, lb, top, left)
+
+	// sum up the cost of the matching
+	for i, j := range matA {
+		if j >= 0 {
+			dist = addCost(dist, c.change(i, j))
+		} else {
+			dist = addCost(dist, c.del(i))
+		}
+	}
+	for j, i := range matB {
+		if i < 0 {
+			dist = addCost(dist, c.ins(j))
+		}
+	}
+	return dist, matA, matB
+}
@@ -0,0 +1,125 @@
+package ed
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestEditDistanceParallel(t *testing.T) {
+	defer func(ts, dc int) {
+		tileSize, directCells = ts, dc
+	}(tileSize, directCells)
+	tileSize, directCells = 3, 4
+
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() string {
+		bs := make([]byte, rnd.Intn(30))
+		for i := range bs {
+			bs[i] = byte('a' + rnd.Intn(4))
+		}
+		return string(bs)
+	}
+	for k := 0; k < 300; k++ {
+		a, b := gen(), gen()
+		ra, rb := []rune(a), []rune(b)
+		// costs depending on positions
+		costOfChange := func(iA, iB int) int {
+			return Ternary(ra[iA] == rb[iB], 0, 2+iA%2)
+		}
+		costOfDel := func(iA int) int {
+			return 1 + iA%3
+		}
+		in := &funcInterface{len(ra), len(rb), costOfChange, costOfDel, ConstCost(2)}
+		d, expA, expB := EditDistanceFull(in)
+		// unit costs with many ties
+		unit := &unitStr{Base{len(ra), len(rb), 1}, ra, rb}
+		unitD, unitA, unitB := EditDistanceFull(unit)
+
+		for _, workers := range []int{1, 2, 8, 0} {
+			name := fmt.Sprintf("%s and %s with %d workers", a, b, workers)
+			assert.Equal(t, "EditDistanceParallel between "+name, EditDistanceParallel(in, workers), d)
+
+			actD, matA, matB := EditDistanceFullParallel(in, workers)
+			assert.Equal(t, "EditDistanceFullParallel between "+name, actD, d)
+			cost := 0
+			for i, j := range matA {
+				if j < 0 {
+					cost += costOfDel(i)
+				} else {
+					assert.Equal(t, "matB[matA[i]]", matB[j], i)
+					cost += costOfChange(i, j)
+				}
+			}
+			for _, i := range matB {
+				if i < 0 {
+					cost += 2
+				}
+			}
+			assert.Equal(t, "cost of matching between "+name, cost, d)
+			assert.StringEqual(t, "matA between "+name, matA, expA)
+			assert.StringEqual(t, "matB between "+name, matB, expB)
+
+			actD, matA, matB = EditDistanceFullParallel(unit, workers)
+			assert.Equal(t, "unit EditDistanceFullParallel between "+name, actD, unitD)
+			assert.StringEqual(t, "unit matA between "+name, matA, unitA)
+			assert.StringEqual(t, "unit matB between "+name, matB, unitB)
+		}
+	}
+}
+
+func TestEditDistanceParallel_Long(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func(n int) []rune {
+		rs := make([]rune, n)
+		for i := range rs {
+			rs[i] = rune('a' + rnd.Intn(4))
+		}
+		return rs
+	}
+	a, b := gen(2000), gen(1500)
+	in := &unitStr{Base{len(a), len(b), 1}, a, b}
+	d, expA, expB := EditDistanceFull(in)
+	assert.Equal(t, "EditDistanceParallel", EditDistanceParallel(in, 4), d)
+	actD, matA, matB := EditDistanceFullParallel(in, 4)
+	assert.Equal(t, "EditDistanceFullParallel", actD, d)
+	assert.StringEqual(t, "matA", matA, expA)
+	assert.StringEqual(t, "matB", matB, expB)
+}
+
+func TestEditDistanceParallel_Equaler(t *testing.T) {
+	defer func(ts, dc int) {
+		tileSize, directCells = ts, dc
+	}(tileSize, directCells)
+	tileSize, directCells = 3, 4
+
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() string {
+		bs := make([]byte, rnd.Intn(20))
+		for i := range bs {
+			bs[i] = byte('a' + rnd.Intn(3))
+		}
+		return string(bs)
+	}
+	for k := 0; k < 300; k++ {
+		a, b := "abc"+gen()+"cba", "abc"+gen()+"cba"
+		in := newEqualStr(a, b)
+		d, expA, expB := EditDistanceFull(in)
+		for _, workers := range []int{1, 4} {
+			name := fmt.Sprintf("%s and %s with %d workers", a, b, workers)
+			assert.Equal(t, "EditDistanceParallel between "+name, EditDistanceParallel(in, workers), EditDistance(in))
+			actD, matA, matB := EditDistanceFullParallel(in, workers)
+			assert.Equal(t, "EditDistanceFullParallel between "+name, actD, d)
+			assert.StringEqual(t, "matA between "+name, matA, expA)
+			assert.StringEqual(t, "matB between "+name, matB, expB)
+		}
+	}
+
+	// trimming is skipped by both if it may change the distance
+	nm := &nonMetric{[]rune("ab"), []rune("b")}
+	assert.Equal(t, "EditDistanceParallel of nonMetric", EditDistanceParallel(nm, 2), 2)
+	d, _, _ := EditDistanceFullParallel(nm, 2)
+	assert.Equal(t, "EditDistanceFullParallel of nonMetric", d, 2)
+}
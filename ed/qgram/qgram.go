@@ -0,0 +1,268 @@
+/*
+qgram package provides q-gram filters that lower-bound the edit-distance of ed.String, and a q-gram inverted index for finding strings within a distance threshold.
+
+A q-gram is a substring of q runes, and a string of length l has l-q+1 positional q-grams. One edit operation destroys at most q q-grams, so if ed(a, b) <= k, a and b share at least max(la, lb)-q+1-kq q-grams (the count filter), and the shared ones can be paired with positions differing by at most k (the position filter). Also |la-lb| <= k (the length filter). These filters never drop a pair within the threshold, and candidates are verified by ed.StringBounded.
+*/
+package qgram
+
+import (
+	"sort"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Gram is a positional q-gram.
+type Gram struct {
+	Gram string
+	Pos  int
+}
+
+// Grams returns the positional q-grams of s, where positions are in runes.
+func Grams(s string, q int) []Gram {
+	rs := []rune(s)
+	if len(rs) < q {
+		return nil
+	}
+	grams := make([]Gram, len(rs)-q+1)
+	for i := range grams {
+		grams[i] = Gram{string(rs[i : i+q]), i}
+	}
+	return grams
+}
+
+// Profile is the number of occurrences of each q-gram in a string.
+type Profile map[string]int
+
+// NewProfile returns the q-gram profile of s.
+func NewProfile(s string, q int) Profile {
+	p := make(Profile)
+	for _, g := range Grams(s, q) {
+		p[g.Gram]++
+	}
+	return p
+}
+
+// Size returns the total number of q-grams.
+func (p Profile) Size() int {
+	n := 0
+	for _, c := range p {
+		n += c
+	}
+	return n
+}
+
+// Common returns the number of q-grams shared by p and o, counting multiplicities.
+func (p Profile) Common(o Profile) int {
+	if len(o) < len(p) {
+		p, o = o, p
+	}
+	n := 0
+	for g, c := range p {
+		if oc := o[g]; oc < c {
+			n += oc
+		} else {
+			n += c
+		}
+	}
+	return n
+}
+
+// Distance returns the q-gram distance, i.e. the L1 distance between two profiles.
+func (p Profile) Distance(o Profile) int {
+	return p.Size() + o.Size() - 2*p.Common(o)
+}
+
+func runeLen(s string) int {
+	n := 0
+	for range s {
+		n++
+	}
+	return n
+}
+
+// LengthBound returns the difference of lengths of two strings in runes, which is a lower bound of their edit-distance.
+func LengthBound(a, b string) int {
+	d := runeLen(a) - runeLen(b)
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// minCommon returns the minimum number of shared q-grams of strings of lengths la and lb within distance k.
+func minCommon(la, lb, q, k int) int {
+	if lb > la {
+		la = lb
+	}
+	return la - q + 1 - k*q
+}
+
+/*
+CountBound returns a lower bound of the edit-distance between two strings with q-gram profiles pa and pb: ceil((max(na, nb)-c)/q), where na and nb are numbers of q-grams and c is the number of shared ones.
+*/
+func CountBound(pa, pb Profile, q int) int {
+	n := pa.Size()
+	if nb := pb.Size(); nb > n {
+		n = nb
+	}
+	return (n - pa.Common(pb) + q - 1) / q
+}
+
+// CountFilter returns false if ed.String(a, b) > k is proved by the length or count filter.
+func CountFilter(a, b string, q, k int) bool {
+	if LengthBound(a, b) > k {
+		return false
+	}
+	return NewProfile(a, q).Common(NewProfile(b, q)) >= minCommon(runeLen(a), runeLen(b), q, k)
+}
+
+// positions groups positions of grams by q-grams, in increasing order.
+func positions(grams []Gram) map[string][]int {
+	m := make(map[string][]int)
+	for _, g := range grams {
+		m[g.Gram] = append(m[g.Gram], g.Pos)
+	}
+	return m
+}
+
+// positionalCommon returns the maximum number of pairs of equal q-grams in ga and gb, with positions differing by at most k.
+func positionalCommon(ga, gb []Gram, k int) int {
+	pb := positions(gb)
+	n := 0
+	for g, as := range positions(ga) {
+		bs := pb[g]
+		// greedy matching of sorted positions is maximum for intervals of the same width
+		for i, j := 0, 0; i < len(as) && j < len(bs); {
+			switch {
+			case bs[j] < as[i]-k:
+				j++
+			case as[i] < bs[j]-k:
+				i++
+			default:
+				n++
+				i, j = i+1, j+1
+			}
+		}
+	}
+	return n
+}
+
+// PositionFilter returns false if ed.String(a, b) > k is proved by the length or position filter. It is at least as strong as CountFilter.
+func PositionFilter(a, b string, q, k int) bool {
+	if LengthBound(a, b) > k {
+		return false
+	}
+	return positionalCommon(Grams(a, q), Grams(b, q), k) >= minCommon(runeLen(a), runeLen(b), q, k)
+}
+
+type posting struct {
+	id, pos int
+}
+
+// Index is a q-gram inverted index of strings.
+type Index struct {
+	q     int
+	strs  []string
+	grams [][]Gram
+	// lists[g] are occurrences of the q-gram g, in increasing order of ids
+	lists map[string][]posting
+	// byLen[l] are ids of strings of length l
+	byLen map[int][]int
+}
+
+// NewIndex returns an empty Index of q-grams, where q > 0.
+func NewIndex(q int) *Index {
+	return &Index{q: q, lists: make(map[string][]posting), byLen: make(map[int][]int)}
+}
+
+// Q returns the q of the index.
+func (idx *Index) Q() int {
+	return idx.q
+}
+
+// Len returns the number of strings in the index.
+func (idx *Index) Len() int {
+	return len(idx.strs)
+}
+
+// String returns the string of id.
+func (idx *Index) String(id int) string {
+	return idx.strs[id]
+}
+
+// Add adds s to the index and returns its id, which is the number of strings added before.
+func (idx *Index) Add(s string) int {
+	id := len(idx.strs)
+	grams := Grams(s, idx.q)
+	idx.strs, idx.grams = append(idx.strs, s), append(idx.grams, grams)
+	for _, g := range grams {
+		idx.lists[g.Gram] = append(idx.lists[g.Gram], posting{id, g.Pos})
+	}
+	l := runeLen(s)
+	idx.byLen[l] = append(idx.byLen[l], id)
+	return id
+}
+
+/*
+Candidates returns ids of strings that pass the length and position filters with s for threshold k, in increasing order. Strings too short for the count filter to prune are always candidates if they pass the length filter.
+
+The time complexity is O(sum of lengths of posting lists of q-grams of s) plus the filtering of candidates.
+*/
+func (idx *Index) Candidates(s string, k int) []int {
+	ls, grams := runeLen(s), Grams(s, idx.q)
+	// counts is an upper bound of the positional common q-grams
+	counts := make(map[int]int)
+	for _, g := range grams {
+		for _, p := range idx.lists[g.Gram] {
+			if p.pos-g.Pos <= k && g.Pos-p.pos <= k {
+				counts[p.id]++
+			}
+		}
+	}
+
+	var ids []int
+	for l := ls - k; l <= ls+k; l++ {
+		t := minCommon(ls, l, idx.q, k)
+		for _, id := range idx.byLen[l] {
+			if t <= 0 || counts[id] >= t && positionalCommon(grams, idx.grams[id], k) >= t {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Match is a string in the index within the threshold.
+type Match struct {
+	ID, Distance int
+}
+
+// Search returns strings within edit-distance k of s, in increasing order of ids. Candidates are verified by ed.StringBounded.
+func (idx *Index) Search(s string, k int) []Match {
+	var res []Match
+	for _, id := range idx.Candidates(s, k) {
+		if d := ed.StringBounded(s, idx.strs[id], k); d <= k {
+			res = append(res, Match{id, d})
+		}
+	}
+	return res
+}
+
+// Pair is a pair of strings within the threshold, where A < B for a self-join.
+type Pair struct {
+	A, B, Distance int
+}
+
+// SelfJoin returns all pairs of strings in the index within edit-distance k, in increasing order of A and then B.
+func (idx *Index) SelfJoin(k int) []Pair {
+	var res []Pair
+	for a, s := range idx.strs {
+		for _, m := range idx.Search(s, k) {
+			if m.ID > a {
+				res = append(res, Pair{a, m.ID, m.Distance})
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,99 @@
+package qgram
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+func TestGrams(t *testing.T) {
+	assert.StringEqual(t, "Grams", Grams("abcab", 2), []Gram{{"ab", 0}, {"bc", 1}, {"ca", 2}, {"ab", 3}})
+	assert.StringEqual(t, "Grams", Grams("中文字", 2), []Gram{{"中文", 0}, {"文字", 1}})
+	assert.Equal(t, "short", len(Grams("a", 2)), 0)
+}
+
+func TestProfile(t *testing.T) {
+	pa, pb := NewProfile("abcab", 2), NewProfile("abab", 2)
+	assert.Equal(t, "Size", pa.Size(), 4)
+	assert.Equal(t, "Common", pa.Common(pb), 2)
+	assert.Equal(t, "Distance", pa.Distance(pb), 3)
+	assert.Equal(t, "CountBound", CountBound(pa, pb, 2), 1)
+}
+
+func TestFilters(t *testing.T) {
+	test := func(a, b string, q, k int, count, position bool) {
+		name := fmt.Sprintf("(%s, %s, %d, %d)", a, b, q, k)
+		assert.Equal(t, "CountFilter"+name, CountFilter(a, b, q, k), count)
+		assert.Equal(t, "PositionFilter"+name, PositionFilter(a, b, q, k), position)
+	}
+
+	test("kitten", "sitting", 2, 3, true, true)
+	test("kitten", "sitting", 2, 1, false, false)
+	test("abcdef", "abc", 2, 2, false, false)
+	// the same q-grams at different positions
+	test("abcxyz", "xyzabc", 3, 1, true, false)
+}
+
+func TestFilters_LowerBound(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() string {
+		rs := make([]rune, rnd.Intn(15))
+		for i := range rs {
+			rs[i] = 'a' + rune(rnd.Intn(3))
+		}
+		return string(rs)
+	}
+	for n := 0; n < 2000; n++ {
+		a, b, q := gen(), gen(), 1+rnd.Intn(3)
+		d := ed.String(a, b)
+		name := fmt.Sprintf("(%s, %s, %d)", a, b, q)
+		assert.True(t, "LengthBound"+name, LengthBound(a, b) <= d)
+		assert.True(t, "CountBound"+name, CountBound(NewProfile(a, q), NewProfile(b, q), q) <= d)
+		assert.True(t, "CountFilter"+name, CountFilter(a, b, q, d))
+		assert.True(t, "PositionFilter"+name, PositionFilter(a, b, q, d))
+	}
+}
+
+func TestIndex(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() string {
+		rs := make([]rune, 3+rnd.Intn(10))
+		for i := range rs {
+			rs[i] = 'a' + rune(rnd.Intn(4))
+		}
+		return string(rs)
+	}
+	idx := NewIndex(2)
+	for i := 0; i < 300; i++ {
+		assert.Equal(t, "Add", idx.Add(gen()), i)
+	}
+	assert.Equal(t, "Len", idx.Len(), 300)
+
+	for k := 0; k <= 3; k++ {
+		var exp []Pair
+		for a := 0; a < idx.Len(); a++ {
+			for b := a + 1; b < idx.Len(); b++ {
+				if d := ed.String(idx.String(a), idx.String(b)); d <= k {
+					exp = append(exp, Pair{a, b, d})
+				}
+			}
+		}
+		assert.StringEqual(t, fmt.Sprintf("SelfJoin(%d)", k), idx.SelfJoin(k), exp)
+	}
+}
+
+func ExampleIndex_Search() {
+	idx := NewIndex(2)
+	for _, s := range []string{"color", "colour", "collar", "cooler", "dollar"} {
+		idx.Add(s)
+	}
+	for _, m := range idx.Search("colr", 1) {
+		fmt.Println(idx.String(m.ID), m.Distance)
+	}
+	// Output:
+	// color 1
+}
@@ -0,0 +1,256 @@
+/*
+render package displays matching results of the ed package (matA and matB returned by ed.EditDistanceFull and alike) in human-readable forms.
+
+ThreeLine renders the classic three-line view:
+
+	abc-de
+	| | *|
+	a-cxfe
+
+SideBySide renders two columns like sdiff(1), with a marker between them: ' ' for equal elements, '|' for changed ones, '<' for deleted ones and '>' for inserted ones.
+*/
+package render
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape codes used when Options.Color is true
+const (
+	colorReset  = "\x1b[0m"
+	colorDel    = "\x1b[31m" // red
+	colorIns    = "\x1b[32m" // green
+	colorChange = "\x1b[33m" // yellow
+)
+
+// Options controls the rendering.
+type Options struct {
+	// FormatA and FormatB return the strings of elements in list A and B. Must be set.
+	FormatA func(iA int) string
+	FormatB func(iB int) string
+
+	// Equal reports whether a matched pair of elements is equal. If nil, the formatted strings are compared.
+	Equal func(iA, iB int) bool
+
+	// Width is the maximum number of runes of a line. For ThreeLine, 0 means no wrapping. For SideBySide, 0 means 80.
+	Width int
+
+	// If Color is true, deleted, inserted and changed elements are colored with ANSI escape codes.
+	Color bool
+}
+
+// Runes returns Options for rendering a matching between the runes of a and b.
+func Runes(a, b string) *Options {
+	ra, rb := []rune(a), []rune(b)
+	return &Options{
+		FormatA: func(iA int) string {
+			return string(ra[iA])
+		},
+		FormatB: func(iB int) string {
+			return string(rb[iB])
+		},
+	}
+}
+
+// Strings returns Options for rendering a matching between two lists of strings, e.g. lines.
+func Strings(a, b []string) *Options {
+	return &Options{
+		FormatA: func(iA int) string {
+			return a[iA]
+		},
+		FormatB: func(iB int) string {
+			return b[iB]
+		},
+	}
+}
+
+// Kinds of a step in an alignment
+const (
+	stepMatch = iota
+	stepChange
+	stepDel
+	stepIns
+)
+
+type step struct {
+	kind   int
+	iA, iB int
+}
+
+// steps converts a matching into an alignment in order. Deletings are placed before insertings.
+func steps(matA, matB []int, opts *Options) []step {
+	var res []step
+	for i, j := 0, 0; i < len(matA) || j < len(matB); {
+		switch {
+		case i < len(matA) && matA[i] < 0:
+			res = append(res, step{stepDel, i, -1})
+			i++
+		case j < len(matB) && matB[j] < 0:
+			res = append(res, step{stepIns, -1, j})
+			j++
+		default:
+			kind := stepChange
+			if opts.Equal != nil && opts.Equal(i, j) || opts.Equal == nil && opts.FormatA(i) == opts.FormatB(j) {
+				kind = stepMatch
+			}
+			res = append(res, step{kind, i, j})
+			i++
+			j++
+		}
+	}
+
+	return res
+}
+
+func width(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// pad returns s padded with spaces or truncated to w runes.
+func pad(s string, w int) string {
+	if n := width(s); n < w {
+		return s + strings.Repeat(" ", w-n)
+	} else if n > w {
+		return string([]rune(s)[:w])
+	}
+
+	return s
+}
+
+func colored(s, color string, opts *Options) string {
+	if !opts.Color || color == "" {
+		return s
+	}
+
+	return color + s + colorReset
+}
+
+func stepColor(kind int) string {
+	switch kind {
+	case stepChange:
+		return colorChange
+	case stepDel:
+		return colorDel
+	case stepIns:
+		return colorIns
+	}
+
+	return ""
+}
+
+/*
+ThreeLine writes the alignment as three lines: elements of A, markers, and elements of B. Gaps are shown as '-', equal elements are marked with '|' and changed ones with '*'.
+
+If any formatted element is not a single rune, columns are separated by a space. If Width is positive, the lines are wrapped into blocks separated by empty lines.
+*/
+func ThreeLine(w io.Writer, matA, matB []int, opts *Options) error {
+	sts := steps(matA, matB, opts)
+
+	// the cells of each column
+	ca, cb := make([]string, len(sts)), make([]string, len(sts))
+	sep := ""
+	for k, st := range sts {
+		if st.iA >= 0 {
+			ca[k] = opts.FormatA(st.iA)
+		}
+		if st.iB >= 0 {
+			cb[k] = opts.FormatB(st.iB)
+		}
+		if width(ca[k]) != 1 && st.iA >= 0 || width(cb[k]) != 1 && st.iB >= 0 {
+			sep = " "
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	var la, lm, lb []string
+	lineW, blocks := 0, 0
+	flush := func() {
+		if len(la) == 0 {
+			return
+		}
+		if blocks > 0 {
+			bw.WriteString("\n")
+		}
+		blocks++
+		bw.WriteString(strings.TrimRight(strings.Join(la, sep), " ") + "\n")
+		bw.WriteString(strings.TrimRight(strings.Join(lm, sep), " ") + "\n")
+		bw.WriteString(strings.TrimRight(strings.Join(lb, sep), " ") + "\n")
+		la, lm, lb, lineW = la[:0], lm[:0], lb[:0], 0
+	}
+	for k, st := range sts {
+		cw := width(ca[k])
+		if n := width(cb[k]); n > cw {
+			cw = n
+		}
+		if cw == 0 {
+			cw = 1
+		}
+		if opts.Width > 0 && len(la) > 0 && lineW+len(sep)+cw > opts.Width {
+			flush()
+		}
+		if len(la) > 0 {
+			lineW += len(sep)
+		}
+		lineW += cw
+
+		a, b := pad(ca[k], cw), pad(cb[k], cw)
+		if st.iA < 0 {
+			a = strings.Repeat("-", cw)
+		}
+		if st.iB < 0 {
+			b = strings.Repeat("-", cw)
+		}
+		m := " "
+		switch st.kind {
+		case stepMatch:
+			m = "|"
+		case stepChange:
+			m = "*"
+		}
+		color := stepColor(st.kind)
+		la = append(la, colored(a, color, opts))
+		lm = append(lm, pad(m, cw))
+		lb = append(lb, colored(b, color, opts))
+	}
+	flush()
+
+	return bw.Flush()
+}
+
+/*
+SideBySide writes the alignment as two columns, one row for each step. The marker between the columns is ' ' for equal elements, '|' for changed ones, '<' for deleted ones and '>' for inserted ones. Elements longer than the column are truncated.
+*/
+func SideBySide(w io.Writer, matA, matB []int, opts *Options) error {
+	total := opts.Width
+	if total <= 0 {
+		total = 80
+	}
+	// left column, " x ", right column
+	cw := (total - 3) / 2
+	if cw < 1 {
+		cw = 1
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, st := range steps(matA, matB, opts) {
+		var a, b, m string
+		switch st.kind {
+		case stepMatch:
+			a, b, m = opts.FormatA(st.iA), opts.FormatB(st.iB), " "
+		case stepChange:
+			a, b, m = opts.FormatA(st.iA), opts.FormatB(st.iB), "|"
+		case stepDel:
+			a, m = opts.FormatA(st.iA), "<"
+		case stepIns:
+			b, m = opts.FormatB(st.iB), ">"
+		}
+		color := stepColor(st.kind)
+		line := colored(pad(a, cw), color, opts) + " " + colored(m, color, opts) + " " + colored(pad(b, cw), color, opts)
+		bw.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	return bw.Flush()
+}
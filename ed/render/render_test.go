@@ -0,0 +1,93 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/daviddengcn/go-algs/ed"
+	"github.com/golangplus/testing/assert"
+)
+
+func matchRunes(a, b string) (matA, matB []int) {
+	ra, rb := []rune(a), []rune(b)
+	_, matA, matB = ed.EditDistanceFFull(len(ra), len(rb), func(iA, iB int) int {
+		return ed.Ternary(ra[iA] == rb[iB], 0, 1)
+	}, ed.ConstCost(1), ed.ConstCost(1))
+
+	return matA, matB
+}
+
+func TestThreeLine(t *testing.T) {
+	test := func(a, b string, width int, exp string) {
+		matA, matB := matchRunes(a, b)
+		opts := Runes(a, b)
+		opts.Width = width
+		var buf bytes.Buffer
+		assert.NoError(t, ThreeLine(&buf, matA, matB, opts))
+		assert.Equal(t, fmt.Sprintf("ThreeLine(%q, %q)", a, b), buf.String(), exp)
+	}
+
+	test("abcde", "acxfe", 0, "abcd-e\n| |* |\na-cxfe\n")
+	test("abcd", "bcde", 0, "abcd-\n |||\n-bcde\n")
+	test("abcd", "bcde", 3, "abc\n ||\n-bc\n\nd-\n|\nde\n")
+	test("", "", 0, "")
+}
+
+func TestThreeLine_Words(t *testing.T) {
+	a, b := strings.Fields("the quick brown fox"), strings.Fields("the slow brown dog jumps")
+	_, matA, matB := ed.EditDistanceFFull(len(a), len(b), func(iA, iB int) int {
+		return ed.Ternary(a[iA] == b[iB], 0, 1)
+	}, ed.ConstCost(1), ed.ConstCost(1))
+
+	var buf bytes.Buffer
+	assert.NoError(t, ThreeLine(&buf, matA, matB, Strings(a, b)))
+	assert.StringEqual(t, "ThreeLine", strings.Split(buf.String(), "\n"), []string{
+		"the quick brown fox -----",
+		"|   *     |     *",
+		"the slow  brown dog jumps",
+		"",
+	})
+}
+
+func TestThreeLine_Color(t *testing.T) {
+	matA, matB := matchRunes("ab", "b")
+	opts := Runes("ab", "b")
+	opts.Color = true
+	var buf bytes.Buffer
+	assert.NoError(t, ThreeLine(&buf, matA, matB, opts))
+	assert.Equal(t, "ThreeLine", buf.String(), "\x1b[31ma\x1b[0mb\n |\n\x1b[31m-\x1b[0mb\n")
+}
+
+func TestSideBySide(t *testing.T) {
+	a := []string{"apple", "banana", "cherry", "date"}
+	b := []string{"apple", "blueberry", "cherry", "elderberry", "fig"}
+	_, matA, matB := ed.EditDistanceFFull(len(a), len(b), func(iA, iB int) int {
+		return ed.Ternary(a[iA] == b[iB], 0, 1)
+	}, ed.ConstCost(1), ed.ConstCost(1))
+
+	opts := Strings(a, b)
+	opts.Width = 19
+	var buf bytes.Buffer
+	assert.NoError(t, SideBySide(&buf, matA, matB, opts))
+	assert.StringEqual(t, "SideBySide", strings.Split(buf.String(), "\n"), []string{
+		"apple      apple",
+		"banana   | blueberr",
+		"cherry     cherry",
+		"date     | elderber",
+		"         > fig",
+		"",
+	})
+}
+
+func ExampleThreeLine() {
+	a, b := "kitten", "sitting"
+	matA, matB := matchRunes(a, b)
+	ThreeLine(os.Stdout, matA, matB, Runes(a, b))
+	// Output:
+	// kitten-
+	// *|||*|
+	// sitting
+}
@@ -0,0 +1,114 @@
+package ed
+
+/*
+Damerau calculates the edit-distance between two strings, where transposing two adjacent runes is also an operation of cost 1. This is the optimal string alignment distance, i.e. no substring is edited more than once. Input strings must be UTF-8 encoded.
+
+The time complexity is O(mn) where m and n are lengths of a and b, and space complexity is O(n).
+*/
+func Damerau(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	lb := len(rb)
+
+	// f2, f1 and f are rows i-2, i-1 and i
+	f2, f1, f := make([]int, lb+1), make([]int, lb+1), make([]int, lb+1)
+	for j := range f {
+		f[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		f2, f1, f = f1, f, f2
+		f[0] = i
+		for j := 1; j <= lb; j++ {
+			mn := min(f1[j]+1, f[j-1]+1) // delete & insert
+			if ra[i-1] == rb[j-1] {
+				mn = min(mn, f1[j-1]) // matched
+			} else {
+				mn = min(mn, f1[j-1]+1) // change
+			}
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				mn = min(mn, f2[j-2]+1) // transpose
+			}
+			f[j] = mn
+		}
+	}
+
+	return f[lb]
+}
+
+/*
+Jaro returns the Jaro similarity between two strings, ranging from 0 (no similarity) to 1 (equal). Input strings must be UTF-8 encoded.
+
+The time complexity is O(mn) where m and n are lengths of a and b.
+*/
+func Jaro(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	// runes are matched if they are equal and not farther than window
+	window := len(ra)
+	if len(rb) > window {
+		window = len(rb)
+	}
+	window = window/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA, matchedB := make([]bool, len(ra)), make([]bool, len(rb))
+	m := 0
+	for i, ca := range ra {
+		lo, hi := i-window, i+window+1
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(rb) {
+			hi = len(rb)
+		}
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && rb[j] == ca {
+				matchedA[i], matchedB[j] = true, true
+				m++
+				break
+			}
+		}
+	}
+	if m == 0 {
+		return 0
+	}
+
+	// half the number of matched runes in different orders
+	t, j := 0, 0
+	for i, ca := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ca != rb[j] {
+			t++
+		}
+		j++
+	}
+
+	fm := float64(m)
+	return (fm/float64(len(ra)) + fm/float64(len(rb)) + (fm-float64(t)/2)/fm) / 3
+}
+
+/*
+JaroWinkler returns the Jaro-Winkler similarity between two strings, ranging from 0 (no similarity) to 1 (equal). The Jaro similarity is boosted by the length of the common prefix, up to 4 runes, with the scaling factor 0.1.
+*/
+func JaroWinkler(a, b string) float64 {
+	sim := Jaro(a, b)
+
+	l := 0
+	for ra, rb := []rune(a), []rune(b); l < 4 && l < len(ra) && l < len(rb) && ra[l] == rb[l]; l++ {
+	}
+
+	return sim + float64(l)*0.1*(1-sim)
+}
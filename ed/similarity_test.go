@@ -0,0 +1,42 @@
+package ed
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestDamerau(t *testing.T) {
+	test := func(a, b string, d int) {
+		actD := Damerau(a, b)
+		assert.Equal(t, fmt.Sprintf("Damerau distance between %s and %s", a, b), actD, d)
+	}
+
+	test("abcd", "bcde", 2)
+	test("abcde", "", 5)
+	test("", "abcde", 5)
+	test("", "", 0)
+	test("abcde", "abcde", 0)
+	test("abcde", "abdce", 1)
+	test("ab", "ba", 1)
+	test("ca", "abc", 3)
+	test("hello", "ehlol", 2)
+	test("héllo", "hlélo", 1)
+}
+
+func TestJaroWinkler(t *testing.T) {
+	test := func(a, b string, jaro, jw float64) {
+		assert.True(t, fmt.Sprintf("Jaro(%s, %s) = %v, expected %v", a, b, Jaro(a, b), jaro), math.Abs(Jaro(a, b)-jaro) < 1e-4)
+		assert.True(t, fmt.Sprintf("JaroWinkler(%s, %s) = %v, expected %v", a, b, JaroWinkler(a, b), jw), math.Abs(JaroWinkler(a, b)-jw) < 1e-4)
+	}
+
+	test("MARTHA", "MARHTA", 0.9444, 0.9611)
+	test("DWAYNE", "DUANE", 0.8222, 0.84)
+	test("DIXON", "DICKSONX", 0.7667, 0.8133)
+	test("abc", "abc", 1, 1)
+	test("abc", "xyz", 0, 0)
+	test("", "", 1, 1)
+	test("a", "", 0, 0)
+}
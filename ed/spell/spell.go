@@ -0,0 +1,194 @@
+/*
+spell package suggests corrections of misspelled words from a dictionary of word frequencies.
+
+Words are indexed by their deletion neighborhoods, i.e. all strings obtained by deleting at most MaxDistance runes, so that every word within MaxDistance (ed.Damerau, with transpositions) of a query shares a deletion with it. Candidates found by the index are verified with ed.Damerau and ranked by a score combining the distance and the frequency.
+
+Usage:
+
+	d := spell.NewDict(2)
+	d.Load(file) // lines of "word frequency"
+	d.Add("gopher", 10)
+	for _, s := range d.Suggest("gohper", 5) {
+		fmt.Println(s.Word, s.Distance, s.Score)
+	}
+
+A Dict is not safe for concurrent use if Add or Load is called.
+*/
+package spell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Dict is an indexed dictionary of words with frequencies.
+type Dict struct {
+	// the maximum Damerau distance of suggestions, fixed when the Dict is created
+	maxDistance int
+
+	// DistanceWeight is the penalty of each edit in the score. The default is ln(1000), i.e. one edit costs as much as a factor of 1000 in frequency.
+	DistanceWeight float64
+
+	freqs map[string]int64
+	// deletes maps a deletion of words to the words
+	deletes map[string][]string
+}
+
+// Suggestion is a suggested word.
+type Suggestion struct {
+	Word     string
+	Distance int
+	Freq     int64
+	// Score is ln(Freq) - DistanceWeight*Distance. Higher is better.
+	Score float64
+}
+
+// NewDict returns an empty Dict which suggests words within maxDistance edits. The index size grows exponentially with maxDistance, 2 is usually enough.
+func NewDict(maxDistance int) *Dict {
+	if maxDistance < 0 {
+		maxDistance = 0
+	}
+	return &Dict{
+		maxDistance:    maxDistance,
+		DistanceWeight: math.Log(1000),
+		freqs:          make(map[string]int64),
+		deletes:        make(map[string][]string),
+	}
+}
+
+// MaxDistance returns the maximum distance of suggestions.
+func (d *Dict) MaxDistance() int {
+	return d.maxDistance
+}
+
+// Len returns the number of words in the dictionary.
+func (d *Dict) Len() int {
+	return len(d.freqs)
+}
+
+// Freq returns the frequency of a word, 0 if it is not in the dictionary.
+func (d *Dict) Freq(word string) int64 {
+	return d.freqs[word]
+}
+
+// deletions calls f with all strings obtained by deleting at most maxDel runes of word, including word itself. A string may be visited more than once.
+func deletions(word []rune, maxDel int, f func(s string)) {
+	f(string(word))
+	if maxDel == 0 {
+		return
+	}
+	// deleting runes in increasing positions only, to avoid most duplicates
+	var rec func(s []rune, from, left int)
+	rec = func(s []rune, from, left int) {
+		for i := from; i < len(s); i++ {
+			del := make([]rune, 0, len(s)-1)
+			del = append(append(del, s[:i]...), s[i+1:]...)
+			f(string(del))
+			if left > 1 {
+				rec(del, i, left-1)
+			}
+		}
+	}
+	rec(word, 0, maxDel)
+}
+
+/*
+Add adds freq to the frequency of a word. A new word is indexed if freq is positive.
+
+The time complexity is O(l^k) where l is the length of the word and k is the MaxDistance.
+*/
+func (d *Dict) Add(word string, freq int64) {
+	if freq <= 0 {
+		return
+	}
+	if _, ok := d.freqs[word]; !ok {
+		seen := make(map[string]bool)
+		deletions([]rune(word), d.maxDistance, func(s string) {
+			if !seen[s] {
+				seen[s] = true
+				d.deletes[s] = append(d.deletes[s], word)
+			}
+		})
+	}
+	d.freqs[word] += freq
+}
+
+/*
+Load adds words from r. Each line contains a word and optionally a frequency separated by white spaces, e.g. "the 23135851162". The frequency is 1 if omitted. Empty lines are ignored.
+*/
+func (d *Dict) Load(r io.Reader) error {
+	s := bufio.NewScanner(r)
+	for ln := 1; s.Scan(); ln++ {
+		fields := strings.Fields(s.Text())
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			d.Add(fields[0], 1)
+		case 2:
+			freq, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("spell: line %d: %v", ln, err)
+			}
+			d.Add(fields[0], freq)
+		default:
+			return fmt.Errorf("spell: line %d: too many fields", ln)
+		}
+	}
+	return s.Err()
+}
+
+/*
+Suggest returns at most k words within MaxDistance of word, in descending order of scores. Ties are broken by distances and then the words. The word itself is included if it is in the dictionary.
+*/
+func (d *Dict) Suggest(word string, k int) []Suggestion {
+	if k <= 0 {
+		return nil
+	}
+
+	rw := []rune(word)
+	checked := make(map[string]bool)
+	var res []Suggestion
+	deletions(rw, d.maxDistance, func(s string) {
+		for _, w := range d.deletes[s] {
+			if checked[w] {
+				continue
+			}
+			checked[w] = true
+			// the lengths differ by at most the distance
+			if l := len([]rune(w)) - len(rw); l > d.maxDistance || l < -d.maxDistance {
+				continue
+			}
+			if dist := ed.Damerau(word, w); dist <= d.maxDistance {
+				freq := d.freqs[w]
+				res = append(res, Suggestion{
+					Word:     w,
+					Distance: dist,
+					Freq:     freq,
+					Score:    math.Log(float64(freq)) - d.DistanceWeight*float64(dist),
+				})
+			}
+		}
+	})
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		if res[i].Distance != res[j].Distance {
+			return res[i].Distance < res[j].Distance
+		}
+		return res[i].Word < res[j].Word
+	})
+	if len(res) > k {
+		res = res[:k]
+	}
+	return res
+}
@@ -0,0 +1,112 @@
+package spell
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/daviddengcn/go-algs/ed"
+	"github.com/golangplus/testing/assert"
+)
+
+const wordList = `the 1000
+then 300
+they 400
+hello 50
+help 80
+world 60
+word 90
+`
+
+func words(sugs []Suggestion) []string {
+	var res []string
+	for _, s := range sugs {
+		res = append(res, s.Word)
+	}
+	return res
+}
+
+func TestSuggest(t *testing.T) {
+	d := NewDict(2)
+	assert.NoError(t, d.Load(strings.NewReader(wordList)))
+	assert.Equal(t, "Len", d.Len(), 7)
+	assert.Equal(t, "Freq(help)", d.Freq("help"), int64(80))
+
+	test := func(word string, k int, exp ...string) {
+		assert.StringEqual(t, fmt.Sprintf("Suggest(%s, %d)", word, k), words(d.Suggest(word, k)), exp)
+	}
+
+	test("teh", 1, "the")
+	test("the", 3, "the", "they", "then")
+	test("hlelo", 5, "hello", "help")
+	test("wrod", 2, "word", "world")
+	test("xyzzy", 5)
+	test("the", 0)
+
+	sugs := d.Suggest("teh", 1)
+	assert.Equal(t, "Distance", sugs[0].Distance, 1)
+	assert.Equal(t, "Freq", sugs[0].Freq, int64(1000))
+
+	// incremental additions
+	d.Add("teh", 2)
+	test("teh", 2, "teh", "the")
+	d.Add("teh", 1)
+	assert.Equal(t, "Freq(teh)", d.Freq("teh"), int64(3))
+
+	// frequency matters if the distance weight is small
+	d.DistanceWeight = 0.1
+	test("teh", 2, "the", "they")
+}
+
+func TestLoad(t *testing.T) {
+	d := NewDict(1)
+	assert.NoError(t, d.Load(strings.NewReader("a\n\nb 3\n")))
+	assert.Equal(t, "Freq(a)", d.Freq("a"), int64(1))
+	assert.Equal(t, "Freq(b)", d.Freq("b"), int64(3))
+	assert.Error(t, d.Load(strings.NewReader("a x\n")))
+	assert.Error(t, d.Load(strings.NewReader("a 1 2\n")))
+}
+
+func TestSuggest_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() string {
+		bs := make([]byte, 1+rnd.Intn(6))
+		for i := range bs {
+			bs[i] = byte('a' + rnd.Intn(3))
+		}
+		return string(bs)
+	}
+	for maxDist := 0; maxDist <= 3; maxDist++ {
+		d := NewDict(maxDist)
+		for i := 0; i < 100; i++ {
+			d.Add(gen(), int64(1+rnd.Intn(10)))
+		}
+		for i := 0; i < 100; i++ {
+			q := gen()
+			exp := 0
+			for w := range d.freqs {
+				if ed.Damerau(q, w) <= maxDist {
+					exp++
+				}
+			}
+			sugs := d.Suggest(q, d.Len())
+			assert.Equal(t, fmt.Sprintf("len(Suggest(%s)) with max distance %d", q, maxDist), len(sugs), exp)
+			for _, s := range sugs {
+				assert.Equal(t, "Distance of "+s.Word, s.Distance, ed.Damerau(q, s.Word))
+			}
+		}
+	}
+}
+
+func ExampleDict_Suggest() {
+	d := NewDict(2)
+	d.Add("gopher", 10)
+	d.Add("goober", 100)
+	for _, s := range d.Suggest("gohper", 5) {
+		fmt.Println(s.Word, s.Distance)
+	}
+	// Output:
+	// gopher 1
+	// goober 2
+}
@@ -0,0 +1,329 @@
+/*
+stochastic package learns the costs of edit operations from string pairs, following "Learning String Edit Distance" by Ristad and Yianilos (1998).
+
+A Model is a memoryless stochastic transducer generating a pair of strings with a sequence of edit operations: substitutions (a, b), deletions (a, ε), insertions (ε, b), and the end. The probabilities of operations are learned by the expectation-maximization (EM) algorithm, and exported as costs of ed.EditDistanceF by negative log probabilities:
+
+	m := stochastic.Train(pairs, 20, 0.01)
+	ra, rb := []rune(a), []rune(b)
+	costOfChange, costOfDel, costOfIns := m.Funcs(ra, rb, 10)
+	d := ed.EditDistanceF(len(ra), len(rb), costOfChange, costOfDel, costOfIns)
+
+Probabilities of strings are products of operation probabilities, which may underflow for strings of thousands of runes.
+*/
+package stochastic
+
+import (
+	"math"
+	"sort"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Pair is a training pair of strings, e.g. a misspelling A and its correction B.
+type Pair struct {
+	A, B string
+}
+
+/*
+Model is the probabilities of edit operations over the alphabets of A and B strings. The probabilities of all operations, including the end, sum to 1.
+*/
+type Model struct {
+	alphaA, alphaB []rune
+	idxA, idxB     map[rune]int
+
+	sub      [][]float64 // sub[a][b]
+	del, ins []float64
+	end      float64
+
+	// the minimum probability, used for runes out of alphabets
+	floor float64
+}
+
+func alphabet(runes map[rune]bool) ([]rune, map[rune]int) {
+	alpha := make([]rune, 0, len(runes))
+	for r := range runes {
+		alpha = append(alpha, r)
+	}
+	sort.Slice(alpha, func(i, j int) bool {
+		return alpha[i] < alpha[j]
+	})
+	idx := make(map[rune]int, len(alpha))
+	for i, r := range alpha {
+		idx[r] = i
+	}
+	return alpha, idx
+}
+
+// NewModel returns a Model with uniform probabilities over the alphabets of runes in pairs.
+func NewModel(pairs []Pair) *Model {
+	ra, rb := make(map[rune]bool), make(map[rune]bool)
+	for _, p := range pairs {
+		for _, r := range p.A {
+			ra[r] = true
+		}
+		for _, r := range p.B {
+			rb[r] = true
+		}
+	}
+
+	m := &Model{}
+	m.alphaA, m.idxA = alphabet(ra)
+	m.alphaB, m.idxB = alphabet(rb)
+	na, nb := len(m.alphaA), len(m.alphaB)
+	p := 1 / float64(na*nb+na+nb+1)
+	m.sub = make([][]float64, na)
+	for a := range m.sub {
+		m.sub[a] = make([]float64, nb)
+		for b := range m.sub[a] {
+			m.sub[a][b] = p
+		}
+	}
+	m.del, m.ins = make([]float64, na), make([]float64, nb)
+	for a := range m.del {
+		m.del[a] = p
+	}
+	for b := range m.ins {
+		m.ins[b] = p
+	}
+	m.end, m.floor = p, p
+	return m
+}
+
+// SubProb returns the probability of substituting a with b.
+func (m *Model) SubProb(a, b rune) float64 {
+	ia, okA := m.idxA[a]
+	ib, okB := m.idxB[b]
+	if !okA || !okB {
+		return m.floor
+	}
+	return m.sub[ia][ib]
+}
+
+// DelProb returns the probability of deleting a.
+func (m *Model) DelProb(a rune) float64 {
+	if ia, ok := m.idxA[a]; ok {
+		return m.del[ia]
+	}
+	return m.floor
+}
+
+// InsProb returns the probability of inserting b.
+func (m *Model) InsProb(b rune) float64 {
+	if ib, ok := m.idxB[b]; ok {
+		return m.ins[ib]
+	}
+	return m.floor
+}
+
+// EndProb returns the probability of ending.
+func (m *Model) EndProb() float64 {
+	return m.end
+}
+
+// indexes returns the indexes of runes in the alphabet, -1 for unknown ones.
+func indexes(s []rune, idx map[rune]int) []int {
+	res := make([]int, len(s))
+	for i, r := range s {
+		if k, ok := idx[r]; ok {
+			res[i] = k
+		} else {
+			res[i] = -1
+		}
+	}
+	return res
+}
+
+func (m *Model) subP(a, b int) float64 {
+	if a < 0 || b < 0 {
+		return m.floor
+	}
+	return m.sub[a][b]
+}
+
+func (m *Model) delP(a int) float64 {
+	if a < 0 {
+		return m.floor
+	}
+	return m.del[a]
+}
+
+func (m *Model) insP(b int) float64 {
+	if b < 0 {
+		return m.floor
+	}
+	return m.ins[b]
+}
+
+// forward returns alpha where alpha[i][j] is the probability of generating a[:i] and b[:j].
+func (m *Model) forward(a, b []int) [][]float64 {
+	alpha := make([][]float64, len(a)+1)
+	for i := range alpha {
+		alpha[i] = make([]float64, len(b)+1)
+		for j := range alpha[i] {
+			p := 0.0
+			if i == 0 && j == 0 {
+				p = 1
+			}
+			if i > 0 {
+				p += m.delP(a[i-1]) * alpha[i-1][j]
+			}
+			if j > 0 {
+				p += m.insP(b[j-1]) * alpha[i][j-1]
+			}
+			if i > 0 && j > 0 {
+				p += m.subP(a[i-1], b[j-1]) * alpha[i-1][j-1]
+			}
+			alpha[i][j] = p
+		}
+	}
+	return alpha
+}
+
+// backward returns beta where beta[i][j] is the probability of generating a[i:] and b[j:], and ending.
+func (m *Model) backward(a, b []int) [][]float64 {
+	beta := make([][]float64, len(a)+1)
+	for i := range beta {
+		beta[i] = make([]float64, len(b)+1)
+	}
+	for i := len(a); i >= 0; i-- {
+		for j := len(b); j >= 0; j-- {
+			p := 0.0
+			if i == len(a) && j == len(b) {
+				p = m.end
+			}
+			if i < len(a) {
+				p += m.delP(a[i]) * beta[i+1][j]
+			}
+			if j < len(b) {
+				p += m.insP(b[j]) * beta[i][j+1]
+			}
+			if i < len(a) && j < len(b) {
+				p += m.subP(a[i], b[j]) * beta[i+1][j+1]
+			}
+			beta[i][j] = p
+		}
+	}
+	return beta
+}
+
+/*
+Prob returns the probability of generating strings a and b, summed over all sequences of edit operations.
+
+The time complexity is O(mn) where m and n are lengths of a and b.
+*/
+func (m *Model) Prob(a, b string) float64 {
+	ia, ib := indexes([]rune(a), m.idxA), indexes([]rune(b), m.idxB)
+	alpha := m.forward(ia, ib)
+	return alpha[len(ia)][len(ib)] * m.end
+}
+
+/*
+Iterate runs one EM iteration on pairs and returns the log-likelihood of pairs before the update. The expected count of every operation is increased by smoothing, which keeps unseen operations possible. Runes out of the alphabets of the model are ignored in counting.
+
+The time complexity is O(sum of mn over pairs) plus the size of the model.
+*/
+func (m *Model) Iterate(pairs []Pair, smoothing float64) (logLikelihood float64) {
+	na, nb := len(m.alphaA), len(m.alphaB)
+	sub := make([][]float64, na)
+	for a := range sub {
+		sub[a] = make([]float64, nb)
+	}
+	del, ins, end := make([]float64, na), make([]float64, nb), 0.0
+
+	for _, pr := range pairs {
+		a, b := indexes([]rune(pr.A), m.idxA), indexes([]rune(pr.B), m.idxB)
+		alpha, beta := m.forward(a, b), m.backward(a, b)
+		p := beta[0][0]
+		if p <= 0 {
+			continue
+		}
+		logLikelihood += math.Log(p)
+		end++
+
+		for i := 0; i <= len(a); i++ {
+			for j := 0; j <= len(b); j++ {
+				if i > 0 && a[i-1] >= 0 {
+					del[a[i-1]] += alpha[i-1][j] * m.del[a[i-1]] * beta[i][j] / p
+				}
+				if j > 0 && b[j-1] >= 0 {
+					ins[b[j-1]] += alpha[i][j-1] * m.ins[b[j-1]] * beta[i][j] / p
+				}
+				if i > 0 && j > 0 && a[i-1] >= 0 && b[j-1] >= 0 {
+					sub[a[i-1]][b[j-1]] += alpha[i-1][j-1] * m.sub[a[i-1]][b[j-1]] * beta[i][j] / p
+				}
+			}
+		}
+	}
+
+	// maximization
+	total := end + smoothing
+	for a := range sub {
+		for b := range sub[a] {
+			sub[a][b] += smoothing
+			total += sub[a][b]
+		}
+		del[a] += smoothing
+		total += del[a]
+	}
+	for b := range ins {
+		ins[b] += smoothing
+		total += ins[b]
+	}
+	if total <= 0 {
+		return logLikelihood
+	}
+
+	m.floor = 1
+	update := func(p *float64, cnt float64) {
+		*p = cnt / total
+		if *p > 0 && *p < m.floor {
+			m.floor = *p
+		}
+	}
+	for a := range sub {
+		for b := range sub[a] {
+			update(&m.sub[a][b], sub[a][b])
+		}
+		update(&m.del[a], del[a])
+	}
+	for b := range ins {
+		update(&m.ins[b], ins[b])
+	}
+	update(&m.end, end+smoothing)
+
+	return logLikelihood
+}
+
+// Train returns a Model trained on pairs by the given number of EM iterations, starting from uniform probabilities.
+func Train(pairs []Pair, iterations int, smoothing float64) *Model {
+	m := NewModel(pairs)
+	for it := 0; it < iterations; it++ {
+		m.Iterate(pairs, smoothing)
+	}
+	return m
+}
+
+// cost returns the cost of an operation of probability p, or ed.Infinite if p is zero or the cost overflows.
+func cost(p, scale float64) int {
+	if p <= 0 {
+		return ed.Infinite
+	}
+	c := math.Round(-math.Log(p) * scale)
+	if c >= float64(ed.Infinite) {
+		return ed.Infinite
+	}
+	return int(c)
+}
+
+/*
+Funcs returns cost functions of runes a and b for ed.EditDistanceF and ed.EditDistanceFFull. The cost of an operation is its negative log probability multiplied by scale and rounded to an integer, so the edit-distance approximates scale times the negative log probability of the most likely sequence of operations (without the end). Runes out of alphabets have the minimum probability of the model. Operations of zero probability, e.g. unseen ones trained without smoothing, cost ed.Infinite, i.e. they are forbidden.
+*/
+func (m *Model) Funcs(a, b []rune, scale float64) (costOfChange func(iA, iB int) int, costOfDel func(iA int) int, costOfIns func(iB int) int) {
+	return func(iA, iB int) int {
+			return cost(m.SubProb(a[iA], b[iB]), scale)
+		}, func(iA int) int {
+			return cost(m.DelProb(a[iA]), scale)
+		}, func(iB int) int {
+			return cost(m.InsProb(b[iB]), scale)
+		}
+}
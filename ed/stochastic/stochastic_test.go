@@ -0,0 +1,97 @@
+package stochastic
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/daviddengcn/go-algs/ed"
+	"github.com/golangplus/testing/assert"
+)
+
+var pairs = []Pair{
+	{"cat", "cet"},
+	{"hat", "het"},
+	{"bat", "bet"},
+	{"tab", "teb"},
+	{"at", "et"},
+	{"cot", "cot"},
+	{"dog", "dg"},
+	{"tag", "teg"},
+}
+
+// totalProb returns the sum of the probabilities of all operations.
+func totalProb(m *Model) float64 {
+	total := m.EndProb()
+	for _, a := range m.alphaA {
+		total += m.DelProb(a)
+		for _, b := range m.alphaB {
+			total += m.SubProb(a, b)
+		}
+	}
+	for _, b := range m.alphaB {
+		total += m.InsProb(b)
+	}
+	return total
+}
+
+func TestNewModel(t *testing.T) {
+	m := NewModel([]Pair{{"ab", "c"}})
+	// 2*1 substitutions, 2 deletions, 1 insertion and the end
+	assert.True(t, "SubProb", math.Abs(m.SubProb('a', 'c')-1.0/6) < 1e-9)
+	assert.True(t, "total", math.Abs(totalProb(m)-1) < 1e-9)
+	// del(a) del(b) ins(c) in 3 orders, and sub(a, c) del(b) or del(a) sub(b, c), followed by the end
+	p := 1.0 / 6
+	exp := 3*math.Pow(p, 4) + 2*math.Pow(p, 3)
+	assert.True(t, fmt.Sprintf("Prob = %v, expected %v", m.Prob("ab", "c"), exp), math.Abs(m.Prob("ab", "c")-exp) < 1e-12)
+	assert.True(t, "Prob of an empty pair", math.Abs(m.Prob("", "")-1.0/6) < 1e-12)
+}
+
+func TestTrain(t *testing.T) {
+	m := NewModel(pairs)
+	last := math.Inf(-1)
+	for it := 0; it < 20; it++ {
+		ll := m.Iterate(pairs, 0.01)
+		assert.True(t, fmt.Sprintf("log-likelihood %v of iteration %d >= %v", ll, it, last), ll >= last-1e-9)
+		last = ll
+		assert.True(t, "total", math.Abs(totalProb(m)-1) < 1e-9)
+	}
+
+	assert.True(t, "a->e is more likely than a->o", m.SubProb('a', 'e') > m.SubProb('a', 'o'))
+	assert.True(t, "t->t is more likely than t->e", m.SubProb('t', 't') > m.SubProb('t', 'e'))
+	assert.True(t, "deleting o is more likely than deleting a", m.DelProb('o') > m.DelProb('a'))
+	assert.True(t, "unknown runes", m.SubProb('x', 'y') > 0)
+
+	dist := func(a, b string) int {
+		ra, rb := []rune(a), []rune(b)
+		costOfChange, costOfDel, costOfIns := m.Funcs(ra, rb, 10)
+		return ed.EditDistanceF(len(ra), len(rb), costOfChange, costOfDel, costOfIns)
+	}
+	assert.True(t, "rat->ret is closer than rat->rot", dist("rat", "ret") < dist("rat", "rot"))
+	assert.True(t, "cat->cet is closer than cat->cxt", dist("cat", "cet") < dist("cat", "cxt"))
+}
+
+func ExampleTrain() {
+	m := Train(pairs, 20, 0.01)
+	fmt.Println(m.SubProb('a', 'e') > m.SubProb('a', 'o'))
+	// Output:
+	// true
+}
+
+func TestTrain_NoSmoothing(t *testing.T) {
+	m := Train([]Pair{{"ab", "ab"}}, 3000, 0)
+	assert.Equal(t, "SubProb(a, b)", m.SubProb('a', 'b'), 0.)
+
+	a, b := []rune("ab"), []rune("ba")
+	change, del, ins := m.Funcs(a, b, 10)
+	assert.Equal(t, "change", change(0, 0), ed.Infinite)
+	assert.Equal(t, "EditDistanceF", ed.EditDistanceF(len(a), len(b), change, del, ins), ed.Infinite)
+	_, err := ed.EditDistanceFChecked(len(a), len(b), change, del, ins)
+	assert.Equal(t, "err", err, ed.ErrNoAlignment)
+
+	// the trained pair is still aligned by matching, where SubProb(a, a) = SubProb(b, b) = 1/3
+	change, del, ins = m.Funcs(a, a, 10)
+	d, err := ed.EditDistanceFChecked(len(a), len(a), change, del, ins)
+	assert.NoError(t, err)
+	assert.Equal(t, "d", d, 2*cost(1./3, 10))
+}
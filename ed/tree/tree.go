@@ -0,0 +1,326 @@
+/*
+tree package provides functions for edit-distance calculation between two ordered trees.
+
+The operations are relabeling a node, deleting a node (its children become children of its parent), and inserting a node. Distance calculates the generalized tree edit-distance, and DistanceFull returns extra node mapping information. The algorithm is described in
+
+	Simple Fast Algorithms for the Editing Distance between Trees and Related Problems.
+	Kaizhong Zhang and Dennis Shasha.
+	SIAM Journal on Computing, December 1989.
+
+Node and LabelTrees are helpers for trees of string labels with unit costs.
+*/
+package tree
+
+/*
+Interface defines a pair of ordered trees and the cost for each operation. Nodes of each tree are identified by indexes from 0 to the length minus one, and the root has index 0.
+*/
+type Interface interface {
+	// LenA returns the number of nodes in the source tree
+	LenA() int
+
+	// LenB returns the number of nodes in the destination tree
+	LenB() int
+
+	// ChildrenA returns the indexes of the children of a node in the source tree, in order
+	ChildrenA(iA int) []int
+
+	// ChildrenB returns the indexes of the children of a node in the destination tree, in order
+	ChildrenB(iB int) []int
+
+	// CostOfChange returns the relabeling cost from a node in the source tree at iA to a node in the destination tree at iB
+	CostOfChange(iA, iB int) int
+
+	// CostOfDel returns the cost of deleting a node in the source tree at iA
+	CostOfDel(iA int) int
+
+	// CostOfIns returns the cost of inserting a node in the destination tree at iB
+	CostOfIns(iB int) int
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// postorder is a tree in post-order. Positions are 1-based, 0 stands for an empty forest.
+type postorder struct {
+	// nodes[k] is the original index of the k-th node
+	nodes []int
+	// lml[k] is the position of the leftmost leaf descendant of the k-th node
+	lml []int
+	// keyroots in increasing order
+	keyroots []int
+}
+
+func newPostorder(n int, children func(int) []int) *postorder {
+	po := &postorder{
+		nodes: make([]int, 1, n+1),
+		lml:   make([]int, 1, n+1),
+	}
+	if n == 0 {
+		return po
+	}
+
+	var visit func(i int) int
+	visit = func(i int) (leftmost int) {
+		for _, c := range children(i) {
+			if l := visit(c); leftmost == 0 {
+				leftmost = l
+			}
+		}
+		po.nodes = append(po.nodes, i)
+		k := len(po.nodes) - 1
+		if leftmost == 0 {
+			leftmost = k
+		}
+		po.lml = append(po.lml, leftmost)
+		return leftmost
+	}
+	visit(0)
+
+	// A keyroot is the root or a node with a left sibling, i.e. the highest node with its leftmost leaf.
+	last := make(map[int]int)
+	for k := 1; k < len(po.nodes); k++ {
+		last[po.lml[k]] = k
+	}
+	for k := 1; k < len(po.nodes); k++ {
+		if last[po.lml[k]] == k {
+			po.keyroots = append(po.keyroots, k)
+		}
+	}
+
+	return po
+}
+
+type zhangShasha struct {
+	in     Interface
+	pa, pb *postorder
+	// td[x][y] is the distance between the subtrees at x and y
+	td [][]int
+}
+
+// forestDist computes the distances between forests l(i)..x and l(j)..y for all x in l(i)..i and y in l(j)..j. fd[x-l(i)+1][y-l(j)+1] is the distance.
+func (zs *zhangShasha) forestDist(i, j int) [][]int {
+	in, pa, pb := zs.in, zs.pa, zs.pb
+	li, lj := pa.lml[i], pb.lml[j]
+	fd := make([][]int, i-li+2)
+	for x := range fd {
+		fd[x] = make([]int, j-lj+2)
+	}
+	for x := 1; x < len(fd); x++ {
+		fd[x][0] = fd[x-1][0] + in.CostOfDel(pa.nodes[x+li-1])
+	}
+	for y := 1; y < len(fd[0]); y++ {
+		fd[0][y] = fd[0][y-1] + in.CostOfIns(pb.nodes[y+lj-1])
+	}
+
+	for x := 1; x < len(fd); x++ {
+		ka := x + li - 1
+		for y := 1; y < len(fd[x]); y++ {
+			kb := y + lj - 1
+			mn := min(fd[x-1][y]+in.CostOfDel(pa.nodes[ka]), fd[x][y-1]+in.CostOfIns(pb.nodes[kb])) // delete & insert
+			if pa.lml[ka] == li && pb.lml[kb] == lj {
+				// both are trees
+				mn = min(mn, fd[x-1][y-1]+in.CostOfChange(pa.nodes[ka], pb.nodes[kb]))
+				zs.td[ka][kb] = mn
+			} else {
+				mn = min(mn, fd[pa.lml[ka]-li][pb.lml[kb]-lj]+zs.td[ka][kb])
+			}
+			fd[x][y] = mn
+		}
+	}
+
+	return fd
+}
+
+func newZhangShasha(in Interface) *zhangShasha {
+	zs := &zhangShasha{
+		in: in,
+		pa: newPostorder(in.LenA(), in.ChildrenA),
+		pb: newPostorder(in.LenB(), in.ChildrenB),
+	}
+	zs.td = make([][]int, len(zs.pa.nodes))
+	for x := range zs.td {
+		zs.td[x] = make([]int, len(zs.pb.nodes))
+	}
+
+	return zs
+}
+
+// run fills td and returns the distance between the two trees.
+func (zs *zhangShasha) run() int {
+	in, pa, pb := zs.in, zs.pa, zs.pb
+	la, lb := len(pa.nodes)-1, len(pb.nodes)-1
+	switch {
+	case la == 0:
+		dist := 0
+		for k := 1; k <= lb; k++ {
+			dist += in.CostOfIns(pb.nodes[k])
+		}
+		return dist
+
+	case lb == 0:
+		dist := 0
+		for k := 1; k <= la; k++ {
+			dist += in.CostOfDel(pa.nodes[k])
+		}
+		return dist
+	}
+
+	for _, i := range pa.keyroots {
+		for _, j := range pb.keyroots {
+			zs.forestDist(i, j)
+		}
+	}
+
+	return zs.td[la][lb]
+}
+
+/*
+Distance returns the tree edit-distance defined by Interface.
+
+The time complexity is O(mn min(dA, lA) min(dB, lB)) where m and n are the numbers of nodes, d is the depth and l is the number of leaves of a tree. The space complexity is O(mn).
+*/
+func Distance(in Interface) int {
+	return newZhangShasha(in).run()
+}
+
+/*
+DistanceFull returns the tree edit-distance and corresponding node mapping defined by Interface.
+Each element in matA and matB is the index of the mapped node in the other tree, if it is equal to or greater than zero; or -1 meaning a deleting or inserting in matA or matB, respectively.
+
+The mapping preserves ancestor and sibling orders. The time and space complexity are the same as Distance.
+*/
+func DistanceFull(in Interface) (dist int, matA, matB []int) {
+	zs := newZhangShasha(in)
+	dist = zs.run()
+
+	matA, matB = make([]int, in.LenA()), make([]int, in.LenB())
+	for i := range matA {
+		matA[i] = -1
+	}
+	for j := range matB {
+		matB[j] = -1
+	}
+
+	pa, pb := zs.pa, zs.pb
+	if len(pa.nodes) == 1 || len(pb.nodes) == 1 {
+		return dist, matA, matB
+	}
+
+	// Reversely find the mapping info for tree pairs
+	pairs := [][2]int{{len(pa.nodes) - 1, len(pb.nodes) - 1}}
+	for len(pairs) > 0 {
+		i, j := pairs[len(pairs)-1][0], pairs[len(pairs)-1][1]
+		pairs = pairs[:len(pairs)-1]
+
+		fd := zs.forestDist(i, j)
+		li, lj := pa.lml[i], pb.lml[j]
+		for x, y := i-li+1, j-lj+1; x > 0 || y > 0; {
+			ka, kb := x+li-1, y+lj-1
+			switch {
+			case x > 0 && fd[x][y] == fd[x-1][y]+in.CostOfDel(pa.nodes[ka]):
+				x--
+			case y > 0 && fd[x][y] == fd[x][y-1]+in.CostOfIns(pb.nodes[kb]):
+				y--
+			case pa.lml[ka] == li && pb.lml[kb] == lj:
+				matA[pa.nodes[ka]], matB[pb.nodes[kb]] = pb.nodes[kb], pa.nodes[ka]
+				x--
+				y--
+			default:
+				pairs = append(pairs, [2]int{ka, kb})
+				x, y = pa.lml[ka]-li, pb.lml[kb]-lj
+			}
+		}
+	}
+
+	return dist, matA, matB
+}
+
+// Node is a node of a labeled tree.
+type Node struct {
+	Label    string
+	Children []*Node
+}
+
+/*
+LabelTrees is an Interface implementation for two trees of Nodes. Relabeling costs 1 if labels are different, and deleting or inserting costs 1.
+*/
+type LabelTrees struct {
+	// Nodes of the trees in pre-order. The indexes are used in the Interface.
+	NodesA, NodesB []*Node
+
+	childrenA, childrenB [][]int
+}
+
+func preorder(root *Node) (nodes []*Node, children [][]int) {
+	if root == nil {
+		return nil, nil
+	}
+
+	var visit func(nd *Node) int
+	visit = func(nd *Node) int {
+		i := len(nodes)
+		nodes = append(nodes, nd)
+		children = append(children, nil)
+		for _, c := range nd.Children {
+			ic := visit(c)
+			children[i] = append(children[i], ic)
+		}
+		return i
+	}
+	visit(root)
+
+	return nodes, children
+}
+
+// NewLabelTrees returns a LabelTrees for trees a and b. A nil root stands for an empty tree.
+func NewLabelTrees(a, b *Node) *LabelTrees {
+	lt := &LabelTrees{}
+	lt.NodesA, lt.childrenA = preorder(a)
+	lt.NodesB, lt.childrenB = preorder(b)
+
+	return lt
+}
+
+// Interface.LenA
+func (lt *LabelTrees) LenA() int {
+	return len(lt.NodesA)
+}
+
+// Interface.LenB
+func (lt *LabelTrees) LenB() int {
+	return len(lt.NodesB)
+}
+
+// Interface.ChildrenA
+func (lt *LabelTrees) ChildrenA(iA int) []int {
+	return lt.childrenA[iA]
+}
+
+// Interface.ChildrenB
+func (lt *LabelTrees) ChildrenB(iB int) []int {
+	return lt.childrenB[iB]
+}
+
+// Interface.CostOfChange
+func (lt *LabelTrees) CostOfChange(iA, iB int) int {
+	if lt.NodesA[iA].Label == lt.NodesB[iB].Label {
+		return 0
+	}
+
+	return 1
+}
+
+// Interface.CostOfDel
+func (lt *LabelTrees) CostOfDel(iA int) int {
+	return 1
+}
+
+// Interface.CostOfIns
+func (lt *LabelTrees) CostOfIns(iB int) int {
+	return 1
+}
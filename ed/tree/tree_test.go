@@ -0,0 +1,149 @@
+package tree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/daviddengcn/go-algs/ed"
+	"github.com/golangplus/testing/assert"
+)
+
+// parse parses a tree in the form of "f(d(a c(b)) e)". Labels are single letters.
+func parse(s string) *Node {
+	var stack []*Node
+	var root, last *Node
+	for _, c := range s {
+		switch c {
+		case '(':
+			stack = append(stack, last)
+		case ')':
+			stack = stack[:len(stack)-1]
+		case ' ':
+		default:
+			last = &Node{Label: string(c)}
+			if len(stack) == 0 {
+				root = last
+			} else {
+				p := stack[len(stack)-1]
+				p.Children = append(p.Children, last)
+			}
+		}
+	}
+
+	return root
+}
+
+func TestDistance(t *testing.T) {
+	test := func(a, b string, d int) {
+		actD := Distance(NewLabelTrees(parse(a), parse(b)))
+		assert.Equal(t, fmt.Sprintf("Tree-edit-distance between %s and %s", a, b), actD, d)
+		actD, _, _ = DistanceFull(NewLabelTrees(parse(a), parse(b)))
+		assert.Equal(t, fmt.Sprintf("Tree-edit-distance between %s and %s", a, b), actD, d)
+	}
+
+	test("f(d(a c(b)) e)", "f(c(d(a b)) e)", 2)
+	test("a(b c)", "a(b c)", 0)
+	test("a(b c)", "a(c b)", 2)
+	test("a", "b", 1)
+	test("a(b(c(d)))", "a(c d)", 3)
+	test("a(b(c d))", "a(c d)", 1)
+	test("", "a(b c)", 3)
+	test("a(b c)", "", 3)
+	test("", "", 0)
+}
+
+func TestDistanceFull(t *testing.T) {
+	test := func(a, b string, d int, matA, matB []int) {
+		actD, actMatA, actMatB := DistanceFull(NewLabelTrees(parse(a), parse(b)))
+		assert.Equal(t, fmt.Sprintf("Tree-edit-distance between %s and %s", a, b), actD, d)
+		assert.StringEqual(t, fmt.Sprintf("matA for mapping between %s and %s", a, b), actMatA, matA)
+		assert.StringEqual(t, fmt.Sprintf("matB for mapping between %s and %s", a, b), actMatB, matB)
+	}
+
+	// pre-order: f d a c b e / f c d a b e
+	test("f(d(a c(b)) e)", "f(c(d(a b)) e)", 2, []int{0, 2, 3, -1, 4, 5}, []int{0, -1, 1, 2, 4, 5})
+	test("a(b c)", "a(x b c)", 1, []int{0, 2, 3}, []int{0, -1, 1, 2})
+	test("a(b(c d))", "a(c d)", 1, []int{0, -1, 1, 2}, []int{0, 2, 3})
+	test("", "a", 1, []int{}, []int{-1})
+}
+
+// chain returns a tree where each node has at most one child.
+func chain(s string) *Node {
+	var root *Node
+	for i := len(s) - 1; i >= 0; i-- {
+		nd := &Node{Label: s[i : i+1]}
+		if root != nil {
+			nd.Children = []*Node{root}
+		}
+		root = nd
+	}
+
+	return root
+}
+
+func TestDistance_Chain(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() string {
+		b := make([]byte, rnd.Intn(10))
+		for i := range b {
+			b[i] = byte('a' + rnd.Intn(3))
+		}
+		return string(b)
+	}
+	for k := 0; k < 200; k++ {
+		a, b := gen(), gen()
+		// the tree edit-distance between chains is the edit-distance between strings
+		assert.Equal(t, fmt.Sprintf("Distance between chains %s and %s", a, b), Distance(NewLabelTrees(chain(a), chain(b))), ed.String(a, b))
+	}
+}
+
+func TestDistanceFull_Mapping(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	var gen func(depth int) *Node
+	gen = func(depth int) *Node {
+		nd := &Node{Label: string(rune('a' + rnd.Intn(3)))}
+		if depth > 0 {
+			for i := rnd.Intn(3); i > 0; i-- {
+				nd.Children = append(nd.Children, gen(depth-1))
+			}
+		}
+		return nd
+	}
+	for k := 0; k < 200; k++ {
+		lt := NewLabelTrees(gen(3), gen(3))
+		dist, matA, matB := DistanceFull(lt)
+		// the cost of the mapping equals to the distance
+		cost := 0
+		for i, j := range matA {
+			if j < 0 {
+				cost += lt.CostOfDel(i)
+			} else {
+				assert.Equal(t, "matB[matA[i]]", matB[j], i)
+				cost += lt.CostOfChange(i, j)
+			}
+		}
+		for _, i := range matB {
+			if i < 0 {
+				cost++
+			}
+		}
+		assert.Equal(t, "cost of mapping", cost, dist)
+	}
+}
+
+func ExampleDistanceFull() {
+	a := &Node{Label: "f", Children: []*Node{{Label: "a"}, {Label: "b"}}}
+	b := &Node{Label: "f", Children: []*Node{{Label: "b"}}}
+	lt := NewLabelTrees(a, b)
+	dist, matA, _ := DistanceFull(lt)
+	fmt.Println(dist)
+	for i, j := range matA {
+		if j < 0 {
+			fmt.Println("delete", lt.NodesA[i].Label)
+		}
+	}
+	// Output:
+	// 1
+	// delete a
+}
@@ -0,0 +1,111 @@
+package ed
+
+/*
+Equaler is an optional interface of an Interface. If implemented, EditDistance, EditDistanceFull, EditDistanceParallel and EditDistanceFullParallel strip the common prefix and suffix of the two lists before the DP and match them, and skip the DP if the lists are identical.
+
+Trimming never changes the distance. It is only done if CostOfChange is zero for every pair in the common prefix and suffix, and CostOfDel and CostOfIns are each the same for all elements, which are checked in O(m+n) time; then some optimal matching matches the common prefix and suffix. If there are multiple optimal matchings, the one returned may differ from that without trimming.
+*/
+type Equaler interface {
+	// EqualAt returns true if the item in the source list at iA is equal to the item in the destination list at iB
+	EqualAt(iA, iB int) bool
+}
+
+// commonEnds returns the lengths of the common prefix and suffix, which do not overlap. Pairs with a non-zero cost of change are not common.
+func commonEnds(in Interface, eq Equaler, la, lb int) (pre, suf int) {
+	for pre < la && pre < lb && eq.EqualAt(pre, pre) && in.CostOfChange(pre, pre) == 0 {
+		pre++
+	}
+	for suf < la-pre && suf < lb-pre && eq.EqualAt(la-1-suf, lb-1-suf) && in.CostOfChange(la-1-suf, lb-1-suf) == 0 {
+		suf++
+	}
+
+	return pre, suf
+}
+
+// constIndel returns true if the costs of deleting and inserting do not depend on elements.
+func constIndel(in Interface, la, lb int) bool {
+	for i := 1; i < la; i++ {
+		if in.CostOfDel(i) != in.CostOfDel(0) {
+			return false
+		}
+	}
+	for j := 1; j < lb; j++ {
+		if in.CostOfIns(j) != in.CostOfIns(0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// trimmed is an Interface of the middle parts of two lists, skipping off elements of both.
+type trimmed struct {
+	in     Interface
+	off    int
+	la, lb int
+}
+
+// Interface.LenA
+func (t *trimmed) LenA() int {
+	return t.la
+}
+
+// Interface.LenB
+func (t *trimmed) LenB() int {
+	return t.lb
+}
+
+// Interface.CostOfChange
+func (t *trimmed) CostOfChange(iA, iB int) int {
+	return t.in.CostOfChange(iA+t.off, iB+t.off)
+}
+
+// Interface.CostOfDel
+func (t *trimmed) CostOfDel(iA int) int {
+	return t.in.CostOfDel(iA + t.off)
+}
+
+// Interface.CostOfIns
+func (t *trimmed) CostOfIns(iB int) int {
+	return t.in.CostOfIns(iB + t.off)
+}
+
+// trim returns the Interface of the middle parts if in implements Equaler and trimming keeps the distance, or nil if nothing is trimmed. pre and suf are the lengths of the common prefix and suffix.
+func trim(in Interface) (mid *trimmed, pre, suf int) {
+	eq, ok := in.(Equaler)
+	if !ok {
+		return nil, 0, 0
+	}
+	la, lb := in.LenA(), in.LenB()
+	pre, suf = commonEnds(in, eq, la, lb)
+	if pre == 0 && suf == 0 || !constIndel(in, la, lb) {
+		return nil, 0, 0
+	}
+
+	return &trimmed{in: in, off: pre, la: la - pre - suf, lb: lb - pre - suf}, pre, suf
+}
+
+// splice returns the matching of the whole lists from the matching of the middle parts.
+func splice(la, lb, pre, suf int, midA, midB []int) (matA, matB []int) {
+	matA, matB = make([]int, la), make([]int, lb)
+	for k := 0; k < pre; k++ {
+		matA[k], matB[k] = k, k
+	}
+	for k := 1; k <= suf; k++ {
+		matA[la-k], matB[lb-k] = lb-k, la-k
+	}
+	for i, j := range midA {
+		if j >= 0 {
+			j += pre
+		}
+		matA[i+pre] = j
+	}
+	for j, i := range midB {
+		if i >= 0 {
+			i += pre
+		}
+		matB[j+pre] = i
+	}
+
+	return matA, matB
+}
@@ -0,0 +1,178 @@
+package ed
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+type equalStr struct {
+	unitStr
+}
+
+func (es *equalStr) EqualAt(iA, iB int) bool {
+	return es.a[iA] == es.b[iB]
+}
+
+func newEqualStr(a, b string) *equalStr {
+	ra, rb := []rune(a), []rune(b)
+	return &equalStr{unitStr{Base{len(ra), len(rb), 1}, ra, rb}}
+}
+
+func TestEqualer(t *testing.T) {
+	test := func(a, b string, d int, matA, matB []int) {
+		in := newEqualStr(a, b)
+		assert.Equal(t, fmt.Sprintf("EditDistance between %s and %s", a, b), EditDistance(in), d)
+		actD, actMatA, actMatB := EditDistanceFull(in)
+		assert.Equal(t, fmt.Sprintf("EditDistanceFull between %s and %s", a, b), actD, d)
+		assert.StringEqual(t, fmt.Sprintf("matA between %s and %s", a, b), actMatA, matA)
+		assert.StringEqual(t, fmt.Sprintf("matB between %s and %s", a, b), actMatB, matB)
+	}
+
+	test("abcde", "abcde", 0, []int{0, 1, 2, 3, 4}, []int{0, 1, 2, 3, 4})
+	test("abxde", "abyde", 1, []int{0, 1, 2, 3, 4}, []int{0, 1, 2, 3, 4})
+	test("abde", "abcde", 1, []int{0, 1, 3, 4}, []int{0, 1, -1, 2, 3})
+	test("aaa", "aa", 1, []int{0, 1, -1}, []int{0, 1})
+	test("", "", 0, []int{}, []int{})
+	test("abc", "", 3, []int{-1, -1, -1}, []int{})
+	test("xbc", "ybz", 2, []int{0, 1, 2}, []int{0, 1, 2})
+}
+
+func TestEqualer_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() string {
+		bs := make([]byte, rnd.Intn(12))
+		for i := range bs {
+			bs[i] = byte('a' + rnd.Intn(3))
+		}
+		return string(bs)
+	}
+	for k := 0; k < 1000; k++ {
+		a, b := gen(), gen()
+		if rnd.Intn(2) == 0 {
+			// common prefix and suffix
+			a, b = "ab"+a+"ba", "ab"+b+"ba"
+		}
+		d := String(a, b)
+		in := newEqualStr(a, b)
+		assert.Equal(t, fmt.Sprintf("EditDistance between %s and %s", a, b), EditDistance(in), d)
+		assert.Equal(t, fmt.Sprintf("EditDistance without Equaler between %s and %s", a, b), EditDistance(&in.unitStr), d)
+
+		actD, matA, matB := EditDistanceFull(in)
+		assert.Equal(t, fmt.Sprintf("EditDistanceFull between %s and %s", a, b), actD, d)
+		// the matching is consistent and its cost is the distance
+		cost := 0
+		for i, j := range matA {
+			if j < 0 {
+				cost++
+			} else {
+				assert.Equal(t, "matB[matA[i]]", matB[j], i)
+				cost += in.CostOfChange(i, j)
+			}
+		}
+		for _, i := range matB {
+			if i < 0 {
+				cost++
+			}
+		}
+		assert.Equal(t, fmt.Sprintf("cost of matching between %s and %s", a, b), cost, d)
+	}
+}
+
+// nonMetric has position-independent costs violating the triangle inequality: deleting 'a' costs more than changing it to 'b' and deleting that.
+type nonMetric struct {
+	a, b []rune
+}
+
+// Interface.LenA
+func (nm *nonMetric) LenA() int {
+	return len(nm.a)
+}
+
+// Interface.LenB
+func (nm *nonMetric) LenB() int {
+	return len(nm.b)
+}
+
+// Interface.CostOfChange
+func (nm *nonMetric) CostOfChange(iA, iB int) int {
+	return Ternary(nm.a[iA] == nm.b[iB], 0, 1)
+}
+
+// Interface.CostOfDel
+func (nm *nonMetric) CostOfDel(iA int) int {
+	return Ternary(nm.a[iA] == 'a', 10, 1)
+}
+
+// Interface.CostOfIns
+func (nm *nonMetric) CostOfIns(iB int) int {
+	return 1
+}
+
+// Equaler.EqualAt
+func (nm *nonMetric) EqualAt(iA, iB int) bool {
+	return nm.a[iA] == nm.b[iB]
+}
+
+// equalFuncs is a funcInterface implementing Equaler.
+type equalFuncs struct {
+	funcInterface
+	a, b []rune
+}
+
+// Equaler.EqualAt
+func (ef *equalFuncs) EqualAt(iA, iB int) bool {
+	return ef.a[iA] == ef.b[iB]
+}
+
+func TestEqualer_Unchanged(t *testing.T) {
+	// trimming the common suffix b would delete a at the cost of 10, so costs of deleting depending on elements are not trimmed
+	in := &nonMetric{[]rune("ab"), []rune("b")}
+	assert.Equal(t, "EditDistance", EditDistance(in), 2)
+	d, matA, matB := EditDistanceFull(in)
+	assert.Equal(t, "EditDistanceFull", d, 2)
+	assert.StringEqual(t, "matA", matA, []int{0, -1})
+	assert.StringEqual(t, "matB", matB, []int{0})
+
+	// equal elements with a non-zero cost of change are not trimmed
+	a, b := []rune("ab"), []rune("ab")
+	change := func(iA, iB int) int {
+		if iA == 0 && iB == 0 {
+			return 5
+		}
+		return Ternary(a[iA] == b[iB], 0, 1)
+	}
+	ef := &equalFuncs{funcInterface{len(a), len(b), change, ConstCost(1), ConstCost(1)}, a, b}
+	// deleting and inserting a
+	assert.Equal(t, "EditDistance with a non-zero cost", EditDistance(ef), 2)
+	d, matA, matB = EditDistanceFull(ef)
+	assert.Equal(t, "EditDistanceFull with a non-zero cost", d, 2)
+	assert.StringEqual(t, "matA with a non-zero cost", matA, []int{-1, 1})
+	assert.StringEqual(t, "matB with a non-zero cost", matB, []int{-1, 1})
+
+	// with constant costs of deleting and inserting, costs of change may depend on positions
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() []rune {
+		rs := make([]rune, rnd.Intn(10))
+		for i := range rs {
+			rs[i] = rune('a' + rnd.Intn(3))
+		}
+		return append(append([]rune("ab"), rs...), 'b', 'a')
+	}
+	for k := 0; k < 300; k++ {
+		a, b := gen(), gen()
+		change := func(iA, iB int) int {
+			return Ternary(a[iA] == b[iB], 0, 1+(iA+2*iB)%4)
+		}
+		ef := &equalFuncs{funcInterface{len(a), len(b), change, ConstCost(2), ConstCost(3)}, a, b}
+		// EditDistanceChecked does not trim
+		exp, err := EditDistanceChecked(ef)
+		assert.NoErrorOrDie(t, err)
+		name := fmt.Sprintf("between %s and %s", string(a), string(b))
+		assert.Equal(t, "EditDistance "+name, EditDistance(ef), exp)
+		d, _, _ := EditDistanceFull(ef)
+		assert.Equal(t, "EditDistanceFull "+name, d, exp)
+	}
+}
@@ -0,0 +1,302 @@
+/*
+linkage package links records referring to the same entity, e.g. customers in different tables, by the Fellegi-Sunter model.
+
+Each field of records has a Comparator deciding whether two values agree, and probabilities M (of agreeing for a matched pair) and U (of agreeing for an unmatched pair). A pair of records scores log2(M/U) for each agreeing field and log2((1-M)/(1-U)) for each disagreeing one, and fields missing in either record score zero. Pairs scoring at least Linker.Upper are matches, and those at least Linker.Lower are possible matches for a review.
+
+Blocking keys limit candidate pairs to records sharing a key, and matches are grouped into clusters by union-find.
+*/
+package linkage
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Record is a list of field values. An empty value is missing.
+type Record []string
+
+// Comparator reports whether two non-empty values of a field agree.
+type Comparator func(a, b string) bool
+
+// Exact is the Comparator of identical values.
+func Exact(a, b string) bool {
+	return a == b
+}
+
+// EditDistance returns a Comparator of values within edit-distance k, computed by ed.StringBounded.
+func EditDistance(k int) Comparator {
+	return func(a, b string) bool {
+		return ed.StringBounded(a, b, k) <= k
+	}
+}
+
+// JaroWinkler returns a Comparator of values whose ed.JaroWinkler similarity is at least threshold.
+func JaroWinkler(threshold float64) Comparator {
+	return func(a, b string) bool {
+		return ed.JaroWinkler(a, b) >= threshold
+	}
+}
+
+// Numeric returns a Comparator of numbers differing by at most tolerance. Values that are not numbers agree only if identical.
+func Numeric(tolerance float64) Comparator {
+	return func(a, b string) bool {
+		x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+		y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+		if errA != nil || errB != nil {
+			return a == b
+		}
+		return math.Abs(x-y) <= tolerance
+	}
+}
+
+// Field defines how a field of records is compared.
+type Field struct {
+	Name    string
+	Compare Comparator
+	// M is the probability of agreeing for a matched pair, and U is that for an unmatched pair; both are in (0, 1)
+	M, U float64
+}
+
+// Weights returns the scores of agreeing and disagreeing.
+func (f *Field) Weights() (agree, disagree float64) {
+	return math.Log2(f.M / f.U), math.Log2((1 - f.M) / (1 - f.U))
+}
+
+// BlockingKey returns the key of a record for blocking. Records with an empty key are not blocked by it.
+type BlockingKey func(r Record) string
+
+// Status is the classification of a pair.
+type Status int
+
+// Classifications of pairs
+const (
+	NonMatch Status = iota
+	Possible
+	Match
+)
+
+// Linker links records by the fields, blocking keys and thresholds.
+type Linker struct {
+	// Fields[i] compares the i-th values of records
+	Fields []Field
+	// Blocking keys; candidates are pairs sharing any key. If empty, all pairs are candidates.
+	Blocking []BlockingKey
+	// Upper is the minimum score of a match, and Lower is that of a possible match
+	Upper, Lower float64
+}
+
+// FieldError is returned by Linker.Validate if a field is invalid.
+type FieldError struct {
+	// Index of the field in Linker.Fields
+	Index int
+	Name  string
+	// Reason is why the field is invalid
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("linkage: field %d (%q): %s", e.Index, e.Name, e.Reason)
+}
+
+// Validate returns a *FieldError if a field has a nil Compare, or M or U out of (0, 1).
+func (l *Linker) Validate() error {
+	for i, f := range l.Fields {
+		switch {
+		case f.Compare == nil:
+			return &FieldError{i, f.Name, "nil Compare"}
+		case !(f.M > 0 && f.M < 1):
+			return &FieldError{i, f.Name, fmt.Sprintf("M %v out of (0, 1)", f.M)}
+		case !(f.U > 0 && f.U < 1):
+			return &FieldError{i, f.Name, fmt.Sprintf("U %v out of (0, 1)", f.U)}
+		}
+	}
+	return nil
+}
+
+// weights returns the scores of agreeing and disagreeing of all fields.
+func (l *Linker) weights() [][2]float64 {
+	ws := make([][2]float64, len(l.Fields))
+	for i := range l.Fields {
+		ws[i][0], ws[i][1] = l.Fields[i].Weights()
+	}
+	return ws
+}
+
+/*
+Score returns the Fellegi-Sunter score of a pair of records. The fields are not validated: the score is undefined if Validate returns an error.
+*/
+func (l *Linker) Score(a, b Record) float64 {
+	return l.score(a, b, l.weights())
+}
+
+func (l *Linker) score(a, b Record, ws [][2]float64) float64 {
+	score := 0.0
+	for i := range l.Fields {
+		if i >= len(a) || i >= len(b) || a[i] == "" || b[i] == "" {
+			continue
+		}
+		if l.Fields[i].Compare(a[i], b[i]) {
+			score += ws[i][0]
+		} else {
+			score += ws[i][1]
+		}
+	}
+	return score
+}
+
+// Classify returns the status of a score.
+func (l *Linker) Classify(score float64) Status {
+	switch {
+	case score >= l.Upper:
+		return Match
+	case score >= l.Lower:
+		return Possible
+	}
+	return NonMatch
+}
+
+// Pair is a pair of linked records.
+type Pair struct {
+	A, B   int
+	Score  float64
+	Status Status
+}
+
+// candidates calls fn with every candidate pair of records, i.e. a[i] and b[j] sharing a blocking key, once.
+func (l *Linker) candidates(a, b []Record, self bool, fn func(i, j int)) {
+	if len(l.Blocking) == 0 {
+		for i := range a {
+			j0 := 0
+			if self {
+				j0 = i + 1
+			}
+			for j := j0; j < len(b); j++ {
+				fn(i, j)
+			}
+		}
+		return
+	}
+
+	seen := make(map[[2]int]bool)
+	for _, key := range l.Blocking {
+		blocks := make(map[string][]int)
+		for j, r := range b {
+			if k := key(r); k != "" {
+				blocks[k] = append(blocks[k], j)
+			}
+		}
+		for i, r := range a {
+			k := key(r)
+			if k == "" {
+				continue
+			}
+			for _, j := range blocks[k] {
+				if self && j <= i || seen[[2]int{i, j}] {
+					continue
+				}
+				seen[[2]int{i, j}] = true
+				fn(i, j)
+			}
+		}
+	}
+}
+
+func sortPairs(pairs []Pair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+}
+
+// Pairs returns matches and possible matches among recs, where A < B, in increasing order of A and then B. The error of Validate is returned if the fields are invalid.
+func (l *Linker) Pairs(recs []Record) ([]Pair, error) {
+	return l.pairs(recs, recs, true)
+}
+
+/*
+Between returns matches and possible matches between records of a and b, where A indexes a and B indexes b, in increasing order of A and then B. To cluster them with Clusters, add len(a) to B. The error of Validate is returned if the fields are invalid.
+*/
+func (l *Linker) Between(a, b []Record) ([]Pair, error) {
+	return l.pairs(a, b, false)
+}
+
+func (l *Linker) pairs(a, b []Record, self bool) ([]Pair, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	ws := l.weights()
+	var res []Pair
+	l.candidates(a, b, self, func(i, j int) {
+		score := l.score(a[i], b[j], ws)
+		if st := l.Classify(score); st != NonMatch {
+			res = append(res, Pair{i, j, score, st})
+		}
+	})
+	sortPairs(res)
+	return res, nil
+}
+
+// unionFind is a disjoint-set forest with path halving and union by size.
+type unionFind struct {
+	parent, size []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n), size: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i], uf.size[i] = i, 1
+	}
+	return uf
+}
+
+func (uf *unionFind) find(x int) int {
+	for uf.parent[x] != x {
+		uf.parent[x] = uf.parent[uf.parent[x]]
+		x = uf.parent[x]
+	}
+	return x
+}
+
+func (uf *unionFind) union(x, y int) {
+	x, y = uf.find(x), uf.find(y)
+	if x == y {
+		return
+	}
+	if uf.size[x] < uf.size[y] {
+		x, y = y, x
+	}
+	uf.parent[y] = x
+	uf.size[x] += uf.size[y]
+}
+
+/*
+Clusters groups n records into clusters connected by pairs of the Match status, by union-find. Each cluster is in increasing order, and clusters are in the order of their first records. Records without matches are clusters of themselves.
+*/
+func Clusters(n int, pairs []Pair) [][]int {
+	uf := newUnionFind(n)
+	for _, p := range pairs {
+		if p.Status == Match {
+			uf.union(p.A, p.B)
+		}
+	}
+	index := make(map[int]int)
+	var clusters [][]int
+	for i := 0; i < n; i++ {
+		root := uf.find(i)
+		c, ok := index[root]
+		if !ok {
+			c = len(clusters)
+			index[root] = c
+			clusters = append(clusters, nil)
+		}
+		clusters[c] = append(clusters[c], i)
+	}
+	return clusters
+}
@@ -0,0 +1,116 @@
+package linkage
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestComparators(t *testing.T) {
+	assert.True(t, "Exact", Exact("a", "a") && !Exact("a", "A"))
+	assert.True(t, "EditDistance", EditDistance(1)("Jon", "John") && !EditDistance(1)("Jon", "Jonathan"))
+	assert.True(t, "JaroWinkler", JaroWinkler(0.9)("MARTHA", "MARHTA") && !JaroWinkler(0.9)("DIXON", "DICKSONX"))
+	assert.True(t, "Numeric", Numeric(0.5)("10", " 10.4") && !Numeric(0.5)("10", "11"))
+	assert.True(t, "Numeric non-numbers", Numeric(1)("n/a", "n/a") && !Numeric(1)("n/a", "1"))
+}
+
+func TestField_Weights(t *testing.T) {
+	f := Field{M: 0.9, U: 0.1}
+	agree, disagree := f.Weights()
+	assert.True(t, fmt.Sprintf("agree %v", agree), math.Abs(agree-math.Log2(9)) < 1e-9)
+	assert.True(t, fmt.Sprintf("disagree %v", disagree), math.Abs(disagree+math.Log2(9)) < 1e-9)
+}
+
+func newLinker() *Linker {
+	return &Linker{
+		Fields: []Field{
+			{Name: "name", Compare: JaroWinkler(0.85), M: 0.9, U: 0.1},
+			{Name: "city", Compare: EditDistance(1), M: 0.8, U: 0.2},
+			{Name: "age", Compare: Numeric(1), M: 0.8, U: 0.2},
+		},
+		Blocking: []BlockingKey{
+			func(r Record) string {
+				if r[0] == "" {
+					return ""
+				}
+				return strings.ToLower(r[0][:1])
+			},
+			func(r Record) string { return r[2] },
+		},
+		Upper: 4,
+		Lower: 0,
+	}
+}
+
+func TestLinker(t *testing.T) {
+	recs := []Record{
+		{"Jonathan Smith", "Boston", "34"},
+		{"Jonathon Smith", "Bostn", "35"},
+		{"Mary Jones", "Chicago", "28"},
+		{"Jon Smith", "Boston", "34"},
+		{"Marie Jones", "Chicago", ""},
+		{"Peter Pan", "London", "12"},
+	}
+	l := newLinker()
+
+	assert.Equal(t, "Classify", l.Classify(l.Score(recs[0], recs[1])), Match)
+	pairs, err := l.Pairs(recs)
+	assert.NoErrorOrDie(t, err)
+	var act []string
+	for _, p := range pairs {
+		act = append(act, fmt.Sprintf("%d-%d:%d", p.A, p.B, p.Status))
+	}
+	assert.StringEqual(t, "Pairs", act, []string{"0-1:2", "0-3:1", "1-3:1", "2-4:2"})
+	assert.StringEqual(t, "Clusters", Clusters(len(recs), pairs), [][]int{{0, 1}, {2, 4}, {3}, {5}})
+
+	// no blocking keys compares all pairs
+	l.Blocking = nil
+	all, err := l.Pairs(recs)
+	assert.NoErrorOrDie(t, err)
+	assert.StringEqual(t, "Pairs without blocking", all, pairs)
+}
+
+func TestLinker_Validate(t *testing.T) {
+	test := func(f Field, reason string) {
+		l := newLinker()
+		l.Fields[1] = f
+		err := l.Validate()
+		assert.Equal(t, "Validate", err, error(&FieldError{1, f.Name, reason}))
+		_, err = l.Pairs([]Record{{"a", "b", "1"}, {"a", "b", "1"}})
+		assert.Equal(t, "Pairs", err, error(&FieldError{1, f.Name, reason}))
+		_, err = l.Between(nil, nil)
+		assert.Equal(t, "Between", err, error(&FieldError{1, f.Name, reason}))
+	}
+	test(Field{Name: "city", M: 0.8, U: 0.2}, "nil Compare")
+	test(Field{Name: "city", Compare: Exact, M: 1, U: 0.2}, "M 1 out of (0, 1)")
+	test(Field{Name: "city", Compare: Exact, M: 0.8}, "U 0 out of (0, 1)")
+	test(Field{Name: "city", Compare: Exact, M: 0.8, U: math.NaN()}, "U NaN out of (0, 1)")
+
+	assert.NoError(t, newLinker().Validate())
+}
+
+func TestLinker_Between(t *testing.T) {
+	a := []Record{{"Jonathan Smith", "Boston", "34"}, {"Mary Jones", "Chicago", "28"}}
+	b := []Record{{"Marie Jones", "Chicago", "29"}, {"Peter Pan", "London", "12"}, {"Jonathon Smith", "Bostn", "35"}}
+	l := newLinker()
+	pairs, err := l.Between(a, b)
+	assert.NoErrorOrDie(t, err)
+	var act []string
+	for _, p := range pairs {
+		act = append(act, fmt.Sprintf("%d-%d:%d", p.A, p.B, p.Status))
+	}
+	assert.StringEqual(t, "Between", act, []string{"0-2:2", "1-0:2"})
+
+	for i := range pairs {
+		pairs[i].B += len(a)
+	}
+	assert.StringEqual(t, "Clusters", Clusters(len(a)+len(b), pairs), [][]int{{0, 4}, {1, 2}, {3}})
+}
+
+func TestClusters(t *testing.T) {
+	pairs := []Pair{{A: 3, B: 4, Status: Match}, {A: 0, B: 4, Status: Match}, {A: 1, B: 2, Status: Possible}, {A: 5, B: 1, Status: Match}}
+	assert.StringEqual(t, "Clusters", Clusters(6, pairs), [][]int{{0, 3, 4}, {1, 5}, {2}})
+}
@@ -0,0 +1,387 @@
+/*
+lsh package finds near-duplicate documents by locality-sensitive hashing, for documents too long to compare by edit-distance pair by pair.
+
+A document is represented by its set of shingles (overlapping k-grams of runes or words), and the similarity of two documents is the Jaccard similarity of their sets. A MinHash signature of n hashes estimates the Jaccard similarity, as each hash of two sets collides with the probability equal to it. The signature is split into b bands of r rows, and documents with an identical band are candidates, so a pair of similarity s becomes a candidate with the probability 1-(1-s^r)^b, which rises steeply around the threshold (1/b)^(1/r).
+
+Candidates can be checked by the estimated similarity and verified by an edit-distance, e.g. EDVerifier.
+*/
+package lsh
+
+import (
+	"encoding/gob"
+	"errors"
+	"hash/fnv"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/daviddengcn/go-algs/ed"
+)
+
+// Shingles returns the distinct k-shingles of runes of s, in the order of first occurrences. A string shorter than k is a shingle itself if not empty.
+func Shingles(s string, k int) []string {
+	rs := []rune(s)
+	if len(rs) < k {
+		if len(rs) == 0 {
+			return nil
+		}
+		return []string{s}
+	}
+	grams := make([]string, 0, len(rs)-k+1)
+	for i := 0; i+k <= len(rs); i++ {
+		grams = append(grams, string(rs[i:i+k]))
+	}
+	return distinct(grams)
+}
+
+// WordShingles returns the distinct k-shingles of words of s, separated by white spaces, in the order of first occurrences. Words of a shingle are joined by a space.
+func WordShingles(s string, k int) []string {
+	words := strings.Fields(s)
+	if len(words) < k {
+		if len(words) == 0 {
+			return nil
+		}
+		return []string{strings.Join(words, " ")}
+	}
+	grams := make([]string, 0, len(words)-k+1)
+	for i := 0; i+k <= len(words); i++ {
+		grams = append(grams, strings.Join(words[i:i+k], " "))
+	}
+	return distinct(grams)
+}
+
+func distinct(strs []string) []string {
+	seen := make(map[string]bool)
+	res := strs[:0]
+	for _, s := range strs {
+		if !seen[s] {
+			seen[s] = true
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// Jaccard returns the Jaccard similarity of two sets of shingles: the size of the intersection over that of the union. It is 1 if both are empty.
+func Jaccard(a, b []string) float64 {
+	setA, setB := make(map[string]bool), make(map[string]bool)
+	for _, s := range a {
+		setA[s] = true
+	}
+	inter := 0
+	for _, s := range b {
+		if !setB[s] {
+			setB[s] = true
+			if setA[s] {
+				inter++
+			}
+		}
+	}
+	union := len(setA) + len(setB) - inter
+	if union == 0 {
+		return 1
+	}
+	return float64(inter) / float64(union)
+}
+
+// Signature is a MinHash signature.
+type Signature []uint64
+
+// Similarity returns the fraction of equal hashes, which estimates the Jaccard similarity. Both signatures must be of the same length.
+func (s Signature) Similarity(o Signature) float64 {
+	if len(s) == 0 {
+		return 1
+	}
+	eq := 0
+	for i, h := range s {
+		if h == o[i] {
+			eq++
+		}
+	}
+	return float64(eq) / float64(len(s))
+}
+
+// mix is the finalizer of splitmix64.
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// MinHash computes MinHash signatures with a fixed family of hash functions.
+type MinHash struct {
+	seeds []uint64
+}
+
+// NewMinHash returns a MinHash of n hash functions, which are determined by seed.
+func NewMinHash(n int, seed int64) *MinHash {
+	m := &MinHash{seeds: make([]uint64, n)}
+	x := uint64(seed)
+	for i := range m.seeds {
+		x = mix(x)
+		m.seeds[i] = x
+	}
+	return m
+}
+
+// Len returns the number of hash functions.
+func (m *MinHash) Len() int {
+	return len(m.seeds)
+}
+
+// Signature returns the MinHash signature of a set of shingles. All hashes are math.MaxUint64 for an empty set.
+func (m *MinHash) Signature(shingles []string) Signature {
+	sig := make(Signature, len(m.seeds))
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for _, s := range shingles {
+		h := fnv.New64a()
+		h.Write([]byte(s))
+		x := h.Sum64()
+		for i, seed := range m.seeds {
+			if v := mix(x ^ seed); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+/*
+BandsFor returns the number of bands and rows per band, whose product is n, such that the threshold (1/b)^(1/r) is closest to threshold.
+*/
+func BandsFor(threshold float64, n int) (bands, rows int) {
+	best := math.Inf(1)
+	for r := 1; r <= n; r++ {
+		if n%r != 0 {
+			continue
+		}
+		b := n / r
+		if d := math.Abs(math.Pow(1/float64(b), 1/float64(r)) - threshold); d < best {
+			best, bands, rows = d, b, r
+		}
+	}
+	return bands, rows
+}
+
+// Options defines the parameters of an Index. A nil *Options means the default values, and so does a zero field.
+type Options struct {
+	// NumHashes is the length of signatures, 128 by default
+	NumHashes int
+	// Threshold is the Jaccard similarity threshold in (0, 1], 0.5 by default
+	Threshold float64
+	// Seed determines the hash functions
+	Seed int64
+}
+
+// Index is an LSH index of MinHash signatures of documents.
+type Index struct {
+	opts        Options
+	bands, rows int
+	minHash     *MinHash
+	sigs        []Signature
+	// buckets[band][h] are ids of documents whose band hashes to h
+	buckets []map[uint64][]int
+}
+
+// ErrInvalidOptions is returned by NewIndex and ReadIndex if NumHashes is negative or Threshold is out of (0, 1].
+var ErrInvalidOptions = errors.New("lsh: invalid options")
+
+// withDefaults returns the options with zero fields filled with the default values.
+func (opts *Options) withDefaults() (Options, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.NumHashes == 0 {
+		o.NumHashes = 128
+	}
+	if o.Threshold == 0 {
+		o.Threshold = 0.5
+	}
+	if o.NumHashes < 1 || !(o.Threshold > 0 && o.Threshold <= 1) {
+		return o, ErrInvalidOptions
+	}
+	return o, nil
+}
+
+// NewIndex returns an empty Index. The numbers of bands and rows are chosen by BandsFor. ErrInvalidOptions is returned if NumHashes is negative or Threshold is out of (0, 1].
+func NewIndex(opts *Options) (*Index, error) {
+	o, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return newIndex(o), nil
+}
+
+func newIndex(o Options) *Index {
+	idx := &Index{opts: o, minHash: NewMinHash(o.NumHashes, o.Seed)}
+	idx.bands, idx.rows = BandsFor(o.Threshold, o.NumHashes)
+	idx.buckets = make([]map[uint64][]int, idx.bands)
+	for b := range idx.buckets {
+		idx.buckets[b] = make(map[uint64][]int)
+	}
+	return idx
+}
+
+// Bands returns the number of bands and rows per band.
+func (idx *Index) Bands() (bands, rows int) {
+	return idx.bands, idx.rows
+}
+
+// Len returns the number of documents in the index.
+func (idx *Index) Len() int {
+	return len(idx.sigs)
+}
+
+// Signature returns the signature of document id.
+func (idx *Index) Signature(id int) Signature {
+	return idx.sigs[id]
+}
+
+// bandHash returns the hash of the b-th band of sig.
+func (idx *Index) bandHash(sig Signature, b int) uint64 {
+	h := uint64(b)
+	for _, v := range sig[b*idx.rows : (b+1)*idx.rows] {
+		h = mix(h ^ v)
+	}
+	return h
+}
+
+func (idx *Index) addSignature(sig Signature) int {
+	id := len(idx.sigs)
+	idx.sigs = append(idx.sigs, sig)
+	for b, bucket := range idx.buckets {
+		h := idx.bandHash(sig, b)
+		bucket[h] = append(bucket[h], id)
+	}
+	return id
+}
+
+// Add adds a document of shingles, and returns its id, which is the number of documents added before.
+func (idx *Index) Add(shingles []string) int {
+	return idx.addSignature(idx.minHash.Signature(shingles))
+}
+
+// candidates returns ids sharing a band with sig, in increasing order.
+func (idx *Index) candidates(sig Signature) []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for b, bucket := range idx.buckets {
+		for _, id := range bucket[idx.bandHash(sig, b)] {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Match is a document similar to a query.
+type Match struct {
+	ID         int
+	Similarity float64
+}
+
+/*
+Query returns documents sharing a band with the document of shingles, whose estimated similarities are at least the threshold, in increasing order of ids. If verify is not nil, only documents with verify(id) true are returned.
+*/
+func (idx *Index) Query(shingles []string, verify func(id int) bool) []Match {
+	sig := idx.minHash.Signature(shingles)
+	var res []Match
+	for _, id := range idx.candidates(sig) {
+		s := sig.Similarity(idx.sigs[id])
+		if s >= idx.opts.Threshold && (verify == nil || verify(id)) {
+			res = append(res, Match{id, s})
+		}
+	}
+	return res
+}
+
+// Pair is a pair of similar documents in the index, where A < B.
+type Pair struct {
+	A, B       int
+	Similarity float64
+}
+
+/*
+Pairs returns pairs of documents in the index sharing a band, whose estimated similarities are at least the threshold, in increasing order of A and then B. If verify is not nil, only pairs with verify(a, b) true are returned.
+*/
+func (idx *Index) Pairs(verify func(a, b int) bool) []Pair {
+	var res []Pair
+	for a, sig := range idx.sigs {
+		for _, b := range idx.candidates(sig) {
+			if b <= a {
+				continue
+			}
+			s := sig.Similarity(idx.sigs[b])
+			if s >= idx.opts.Threshold && (verify == nil || verify(a, b)) {
+				res = append(res, Pair{a, b, s})
+			}
+		}
+	}
+	return res
+}
+
+/*
+EDVerifier returns a verifier for Pairs, which accepts two documents if their edit-distance (ed.String) is at most maxRatio times the length (in runes) of the longer one. It is computed by ed.StringBounded, which stops early on dissimilar pairs.
+*/
+func EDVerifier(docs []string, maxRatio float64) func(a, b int) bool {
+	return func(a, b int) bool {
+		l := len([]rune(docs[a]))
+		if lb := len([]rune(docs[b])); lb > l {
+			l = lb
+		}
+		k := int(maxRatio * float64(l))
+		return ed.StringBounded(docs[a], docs[b], k) <= k
+	}
+}
+
+// serialized is the gob format of an Index. Buckets are rebuilt from signatures.
+type serialized struct {
+	Options    Options
+	Signatures []Signature
+}
+
+// WriteTo writes the index to w in the gob format.
+func (idx *Index) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	err := gob.NewEncoder(cw).Encode(serialized{idx.opts, idx.sigs})
+	return cw.n, err
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// ReadIndex reads an Index written by Index.WriteTo. ErrInvalidOptions is returned if the options read are invalid.
+func ReadIndex(r io.Reader) (*Index, error) {
+	var s serialized
+	if err := gob.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+	o, err := s.Options.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	idx := newIndex(o)
+	for _, sig := range s.Signatures {
+		if len(sig) != o.NumHashes {
+			return nil, errors.New("lsh: signature length mismatch")
+		}
+		idx.addSignature(sig)
+	}
+	return idx, nil
+}
@@ -0,0 +1,170 @@
+package lsh
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestShingles(t *testing.T) {
+	assert.StringEqual(t, "Shingles", Shingles("abcab", 2), []string{"ab", "bc", "ca"})
+	assert.StringEqual(t, "short", Shingles("a", 2), []string{"a"})
+	assert.Equal(t, "empty", len(Shingles("", 2)), 0)
+	assert.StringEqual(t, "WordShingles", WordShingles("a rose is a rose", 2), []string{"a rose", "rose is", "is a"})
+	assert.StringEqual(t, "WordShingles short", WordShingles(" a  rose ", 3), []string{"a rose"})
+}
+
+func TestJaccard(t *testing.T) {
+	assert.Equal(t, "Jaccard", Jaccard([]string{"a", "b", "c"}, []string{"b", "c", "d", "d"}), 0.5)
+	assert.Equal(t, "empty", Jaccard(nil, nil), 1.)
+	assert.Equal(t, "disjoint", Jaccard([]string{"a"}, []string{"b"}), 0.)
+}
+
+func TestMinHash(t *testing.T) {
+	m := NewMinHash(512, 1)
+	assert.Equal(t, "Len", m.Len(), 512)
+	a := WordShingles("the quick brown fox jumps over the lazy dog and runs away into the forest", 1)
+	b := WordShingles("the quick brown fox leaps over the lazy cat and runs away into the woods", 1)
+	exp, act := Jaccard(a, b), m.Signature(a).Similarity(m.Signature(b))
+	assert.True(t, fmt.Sprintf("estimated %v, exact %v", act, exp), math.Abs(act-exp) < 0.1)
+	assert.Equal(t, "same", m.Signature(a).Similarity(NewMinHash(512, 1).Signature(a)), 1.)
+}
+
+func TestBandsFor(t *testing.T) {
+	test := func(threshold float64, n, bands, rows int) {
+		b, r := BandsFor(threshold, n)
+		assert.Equal(t, fmt.Sprintf("BandsFor(%v, %d)", threshold, n), [2]int{b, r}, [2]int{bands, rows})
+	}
+
+	test(0.5, 128, 32, 4)
+	test(0.8, 128, 8, 16)
+	test(0.5, 1, 1, 1)
+}
+
+func randomDoc(rnd *rand.Rand, words int) string {
+	ws := make([]string, words)
+	for i := range ws {
+		ws[i] = fmt.Sprintf("w%d", rnd.Intn(1000))
+	}
+	return strings.Join(ws, " ")
+}
+
+// mutate replaces n random words of doc.
+func mutate(rnd *rand.Rand, doc string, n int) string {
+	ws := strings.Fields(doc)
+	for i := 0; i < n; i++ {
+		ws[rnd.Intn(len(ws))] = fmt.Sprintf("x%d", rnd.Intn(1000))
+	}
+	return strings.Join(ws, " ")
+}
+
+func TestIndex(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var docs []string
+	for i := 0; i < 50; i++ {
+		d := randomDoc(rnd, 200)
+		// a near-duplicate of each document
+		docs = append(docs, d, mutate(rnd, d, 5))
+	}
+	idx, err := NewIndex(&Options{NumHashes: 128, Threshold: 0.7, Seed: 1})
+	assert.NoErrorOrDie(t, err)
+	for i, d := range docs {
+		assert.Equal(t, "Add", idx.Add(WordShingles(d, 3)), i)
+	}
+	assert.Equal(t, "Len", idx.Len(), len(docs))
+
+	pairs := idx.Pairs(nil)
+	assert.Equal(t, "len(pairs)", len(pairs), 50)
+	for _, p := range pairs {
+		assert.Equal(t, fmt.Sprintf("%v", p), [2]int{p.A, p.B}, [2]int{p.A - p.A%2, p.A - p.A%2 + 1})
+	}
+	assert.StringEqual(t, "Pairs verified", idx.Pairs(EDVerifier(docs, 0.05)), pairs)
+	assert.Equal(t, "Pairs rejected", len(idx.Pairs(EDVerifier(docs, 0.001))), 0)
+
+	ms := idx.Query(WordShingles(mutate(rnd, docs[10], 2), 3), nil)
+	assert.Equal(t, "len(Query)", len(ms), 2)
+	assert.Equal(t, "Query[0]", ms[0].ID, 10)
+}
+
+func TestNewIndex_Options(t *testing.T) {
+	test := func(opts *Options, numHashes int, threshold float64) {
+		idx, err := NewIndex(opts)
+		assert.NoErrorOrDie(t, err)
+		assert.Equal(t, fmt.Sprintf("NumHashes of %+v", opts), len(idx.minHash.seeds), numHashes)
+		bands, rows := idx.Bands()
+		expBands, expRows := BandsFor(threshold, numHashes)
+		assert.Equal(t, fmt.Sprintf("bands of %+v", opts), bands, expBands)
+		assert.Equal(t, fmt.Sprintf("rows of %+v", opts), rows, expRows)
+	}
+	test(nil, 128, 0.5)
+	test(&Options{}, 128, 0.5)
+	test(&Options{Threshold: 0.8}, 128, 0.8)
+	test(&Options{NumHashes: 64}, 64, 0.5)
+	test(&Options{Seed: 3}, 128, 0.5)
+
+	for _, opts := range []*Options{{NumHashes: -1}, {Threshold: -0.5}, {Threshold: 1.5}, {Threshold: math.NaN()}} {
+		idx, err := NewIndex(opts)
+		assert.Equal(t, fmt.Sprintf("error of %+v", opts), err, ErrInvalidOptions)
+		assert.True(t, fmt.Sprintf("nil Index of %+v", opts), idx == nil)
+	}
+
+	var buf bytes.Buffer
+	assert.NoErrorOrDie(t, gob.NewEncoder(&buf).Encode(serialized{Options: Options{NumHashes: -1}}))
+	_, err := ReadIndex(&buf)
+	assert.Equal(t, "ReadIndex", err, ErrInvalidOptions)
+}
+
+func TestIndex_Serialize(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	idx, err := NewIndex(nil)
+	assert.NoErrorOrDie(t, err)
+	var docs []string
+	for i := 0; i < 20; i++ {
+		d := randomDoc(rnd, 100)
+		docs = append(docs, d, mutate(rnd, d, 3))
+	}
+	for _, d := range docs {
+		idx.Add(Shingles(d, 5))
+	}
+
+	var buf bytes.Buffer
+	n, err := idx.WriteTo(&buf)
+	assert.NoErrorOrDie(t, err)
+	assert.Equal(t, "n", n, int64(buf.Len()))
+
+	loaded, err := ReadIndex(&buf)
+	assert.NoErrorOrDie(t, err)
+	assert.Equal(t, "Len", loaded.Len(), idx.Len())
+	assert.StringEqual(t, "Pairs", loaded.Pairs(nil), idx.Pairs(nil))
+	q := Shingles(docs[3], 5)
+	assert.StringEqual(t, "Query", loaded.Query(q, nil), idx.Query(q, nil))
+
+	_, err = ReadIndex(strings.NewReader("bad"))
+	assert.Error(t, err)
+}
+
+func ExampleIndex_Pairs() {
+	docs := []string{
+		"the quick brown fox jumps over the lazy dog",
+		"the quick brown fox jumped over the lazy dog",
+		"lorem ipsum dolor sit amet consectetur adipiscing elit",
+	}
+	idx, err := NewIndex(&Options{NumHashes: 128, Threshold: 0.6, Seed: 1})
+	if err != nil {
+		panic(err)
+	}
+	for _, d := range docs {
+		idx.Add(Shingles(d, 3))
+	}
+	for _, p := range idx.Pairs(EDVerifier(docs, 0.1)) {
+		fmt.Println(p.A, p.B)
+	}
+	// Output:
+	// 0 1
+}